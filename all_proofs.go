@@ -0,0 +1,62 @@
+package fastcommit
+
+import (
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// maxStaleSlots is the number of updated slots past which the cached proofs are dropped:
+// adjusting every proof for a slot costs POLY_SIZE group operations, FK20 about log2(POLY_SIZE) times as many.
+const maxStaleSlots = 12
+
+// AllProofs returns the opening proofs of every slot of the branch, computed at once with FK20.
+//
+// The proofs are cached, and kept valid across updates of the branch with update keys.
+// The result is a copy, the caller may modify it.
+func (s *ValueCommit) AllProofs() ([]bls12381.G1Affine, error) {
+	if s.proofs != nil && s.applyStale() == nil {
+		return append([]bls12381.G1Affine(nil), s.proofs...), nil
+	}
+
+	fk, err := s.context().getFK20()
+	if nil != err {
+		return nil, err
+	}
+	proofs, err := fk.ComputeAllProofs(s.values)
+	if nil != err {
+		return nil, err
+	}
+	s.proofs = proofs
+	return append([]bls12381.G1Affine(nil), proofs...), nil
+}
+
+// ProofForIndex returns the opening proof of slot index, from the cache filled by AllProofs if there is one.
+func (s *ValueCommit) ProofForIndex(index int) (bls12381.G1Affine, error) {
+	if index < 0 || index >= POLY_SIZE {
+		return bls12381.G1Affine{}, ErrSlotOutOfRange
+	}
+	if s.proofs != nil {
+		proof := s.proofs[index]
+		keys := s.context().getUpdateKeys()
+		for j, delta := range s.stale {
+			if err := keys.UpdateProof(&proof, uint64(index), uint64(j), delta); nil != err {
+				return bls12381.G1Affine{}, err
+			}
+		}
+		return proof, nil
+	}
+	return s.ProofForVal(s.context().domain.Roots[index])
+}
+
+// applyStale adjusts the cached proofs for the slots updated since they were computed,
+// and drops them if that fails.
+func (s *ValueCommit) applyStale() error {
+	keys := s.context().getUpdateKeys()
+	for j, delta := range s.stale {
+		if err := keys.UpdateProofs(s.proofs, uint64(j), delta); nil != err {
+			s.proofs, s.stale = nil, nil
+			return err
+		}
+		delete(s.stale, j)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package fastcommit
+
+import (
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestValueCommit_AllProofs(t *testing.T) {
+	fc := NewContext(dataCase)
+	proofs, err := fc.AllProofs()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, POLY_SIZE, len(proofs))
+
+	for _, i := range []int{0, 1, 2048, 4095} {
+		expected, err := fc.ProofForVal(DefaultKZGContext().domain.Roots[i])
+		assert.Equal(t, nil, err)
+		assert.Equal(t, expected, proofs[i])
+	}
+
+	// the proofs handed out do not alias the cache
+	proofs[7] = proofs[9]
+	proof, err := fc.ProofForIndex(7)
+	assert.Equal(t, nil, err)
+	assert.NotEqual(t, proofs[9], proof)
+
+	// the cache follows the updates of the branch
+	err = fc.Update(7, fr.NewElement(7))
+	assert.Equal(t, nil, err)
+	for _, i := range []int{7, 9} {
+		proof, err := fc.ProofForIndex(i)
+		assert.Equal(t, nil, err)
+		err = fc.VerifyForVal(DefaultKZGContext().domain.Roots[i], fc.values[i], proof)
+		assert.Equal(t, nil, err)
+	}
+
+	// updates are only recorded, and applied to the whole cache when it is read
+	err = fc.Update(7, fr.NewElement(8))
+	assert.Equal(t, nil, err)
+	err = fc.Update(11, fr.NewElement(11))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(fc.stale))
+	proofs, err = fc.AllProofs()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(fc.stale))
+	for _, i := range []int{7, 11, 4095} {
+		err = fc.VerifyForVal(DefaultKZGContext().domain.Roots[i], fc.values[i], proofs[i])
+		assert.Equal(t, nil, err)
+	}
+
+	// past maxStaleSlots slots the cache is dropped and recomputed on demand
+	for i := 0; i <= maxStaleSlots; i++ {
+		err = fc.Update(100+i, fr.NewElement(uint64(i)))
+		assert.Equal(t, nil, err)
+	}
+	assert.Equal(t, true, fc.proofs == nil)
+	proofs, err = fc.AllProofs()
+	assert.Equal(t, nil, err)
+	err = fc.VerifyForVal(DefaultKZGContext().domain.Roots[100], fc.values[100], proofs[100])
+	assert.Equal(t, nil, err)
+}
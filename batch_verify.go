@@ -0,0 +1,127 @@
+package fastcommit
+
+import (
+	"crypto/rand"
+	"errors"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	crateKzg "github/yyjia/fastcommit/crateKzg/kzg"
+	"io"
+	"sort"
+)
+
+var ErrInvalidMultiProofs = errors.New("some multiproofs are invalid")
+
+// TreeProof is a multiproof of a key of the tree, as checked by Material.Verify.
+type TreeProof struct {
+	Material *Material
+	Params   NeedParams
+	D        bls12381.G1Affine
+	Proof    bls12381.G1Affine
+}
+
+// VerifyMultiProofs verifies many independent multiproofs with a single pairing check.
+//
+// If the batch fails, it is bisected until every invalid proof is found. The indexes
+// of those proofs are returned, in increasing order, along with ErrInvalidMultiProofs.
+// Other errors, such as a failure to read the batching randomness, are returned as they are.
+func VerifyMultiProofs(proofs []TreeProof) ([]int, error) {
+	return DefaultKZGContext().VerifyMultiProofs(proofs)
+}
+
+// VerifyMultiProofsWithReader is VerifyMultiProofs with the batching randomness read from reader.
+func VerifyMultiProofsWithReader(proofs []TreeProof, reader io.Reader) ([]int, error) {
+	return DefaultKZGContext().VerifyMultiProofsWithReader(proofs, reader)
+}
+
+// VerifyMultiProofsDeterministic is VerifyMultiProofs with the batching scalars derived from transcripts
+// over the batches, see crateKzg.BatchVerifyMultiPointsDeterministic.
+func VerifyMultiProofsDeterministic(proofs []TreeProof) ([]int, error) {
+	return DefaultKZGContext().VerifyMultiProofsDeterministic(proofs)
+}
+
+// VerifyMultiProofs is VerifyMultiProofs against the setup of c.
+// The proofs must be made over c, the others are reported as invalid.
+func (c *KZGContext) VerifyMultiProofs(proofs []TreeProof) ([]int, error) {
+	return c.VerifyMultiProofsWithReader(proofs, rand.Reader)
+}
+
+// VerifyMultiProofsWithReader is VerifyMultiProofsWithReader against the setup of c.
+func (c *KZGContext) VerifyMultiProofsWithReader(proofs []TreeProof, reader io.Reader) ([]int, error) {
+	openKey := c.getPreparedKey()
+	return c.verifyMultiProofs(proofs, func(commits []bls12381.G1Affine, openings []crateKzg.OpeningProof) error {
+		return openKey.BatchVerifyMultiPointsWithReader(commits, openings, reader)
+	})
+}
+
+// VerifyMultiProofsDeterministic is VerifyMultiProofsDeterministic against the setup of c.
+func (c *KZGContext) VerifyMultiProofsDeterministic(proofs []TreeProof) ([]int, error) {
+	return c.verifyMultiProofs(proofs, c.getPreparedKey().BatchVerifyMultiPointsDeterministic)
+}
+
+// batchVerifier checks a batch of openings with a single pairing check.
+type batchVerifier func(commits []bls12381.G1Affine, openings []crateKzg.OpeningProof) error
+
+// verifyMultiProofs reduces every proof to an opening and checks them with verify.
+func (c *KZGContext) verifyMultiProofs(proofs []TreeProof, verify batchVerifier) ([]int, error) {
+	// proofs that cannot be reduced to an opening over c are invalid on their own
+	var invalid, batched []int
+	commits := make([]bls12381.G1Affine, 0, len(proofs))
+	openings := make([]crateKzg.OpeningProof, 0, len(proofs))
+	for i := range proofs {
+		p := &proofs[i]
+		if p.Material == nil || p.Material.context() != c {
+			invalid = append(invalid, i)
+			continue
+		}
+		// an empty or malformed path opens nothing
+		commit, opening, err := p.Material.opening(p.Params, p.D, p.Proof)
+		if nil != err {
+			invalid = append(invalid, i)
+			continue
+		}
+		commits = append(commits, commit)
+		openings = append(openings, opening)
+		batched = append(batched, i)
+	}
+
+	failed, err := bisectInvalid(commits, openings, verify, 0)
+	if nil != err {
+		return nil, err
+	}
+	for _, j := range failed {
+		invalid = append(invalid, batched[j])
+	}
+	if len(invalid) != 0 {
+		sort.Ints(invalid)
+		return invalid, ErrInvalidMultiProofs
+	}
+	return nil, nil
+}
+
+// bisectInvalid returns the indexes, shifted by offset, of the openings that do not verify.
+// A batch that verifies as a whole costs one pairing check, so only failing halves are split.
+//
+// Only a failed pairing check makes a batch invalid, any other error of verify is returned.
+func bisectInvalid(commits []bls12381.G1Affine, openings []crateKzg.OpeningProof, verify batchVerifier, offset int) ([]int, error) {
+	err := verify(commits, openings)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, crateKzg.ErrVerifyOpeningProof) {
+		return nil, err
+	}
+	if len(openings) == 1 {
+		return []int{offset}, nil
+	}
+
+	mid := len(openings) / 2
+	invalid, err := bisectInvalid(commits[:mid], openings[:mid], verify, offset)
+	if nil != err {
+		return nil, err
+	}
+	rest, err := bisectInvalid(commits[mid:], openings[mid:], verify, offset+mid)
+	if nil != err {
+		return nil, err
+	}
+	return append(invalid, rest...), nil
+}
@@ -0,0 +1,64 @@
+package fastcommit
+
+import (
+	"bytes"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"testing"
+)
+
+// buildTestTree commits dataCase as the only leaf branch of a tree of depth 3, over a context of its own.
+func buildTestTree() *KZGContext {
+	ctx := embeddedContext()
+	ctx.branchs = []*ValueCommit{ctx.NewValueCommit(dataCase)}
+	ctx.UpdatesRoot()
+	return ctx
+}
+
+func treeProofFor(t *testing.T, ctx *KZGContext, k uint32) TreeProof {
+	instance := ctx.NewMaterial(k, ctx.branchs[0].values[k])
+	np := instance.parseParams()
+	D, proof, err := instance.Prove(np)
+	assert.Equal(t, nil, err)
+	return TreeProof{Material: instance, Params: np, D: D, Proof: proof}
+}
+
+func TestVerifyMultiProofs(t *testing.T) {
+	ctx := buildTestTree()
+	proofs := []TreeProof{treeProofFor(t, ctx, 1), treeProofFor(t, ctx, 5), treeProofFor(t, ctx, 7), treeProofFor(t, ctx, 9)}
+
+	invalid, err := ctx.VerifyMultiProofs(proofs)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(invalid))
+
+	// tamper with two proofs, bisection must find exactly those
+	_, _, g1, _ := bls12381.Generators()
+	proofs[1].Proof.Add(&proofs[1].Proof, &g1)
+	proofs[3].D = g1
+	invalid, err = ctx.VerifyMultiProofs(proofs)
+	assert.Equal(t, ErrInvalidMultiProofs, err)
+	assert.Equal(t, []int{1, 3}, invalid)
+	invalid, err = ctx.VerifyMultiProofsDeterministic(proofs)
+	assert.Equal(t, ErrInvalidMultiProofs, err)
+	assert.Equal(t, []int{1, 3}, invalid)
+
+	invalid, err = VerifyMultiProofs([]TreeProof{{Material: &Material{}}})
+	assert.Equal(t, ErrInvalidMultiProofs, err)
+	assert.Equal(t, []int{0}, invalid)
+
+	// proofs without a path, or over another context, are reported along with the ones found by bisection
+	other := buildTestTree()
+	proofs = []TreeProof{{}, proofs[0], proofs[1], {Material: &Material{}}, proofs[2], treeProofFor(t, other, 1)}
+	invalid, err = ctx.VerifyMultiProofs(proofs)
+	assert.Equal(t, ErrInvalidMultiProofs, err)
+	assert.Equal(t, []int{0, 2, 3, 5}, invalid)
+	invalid, err = other.VerifyMultiProofs(proofs[5:])
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(invalid))
+
+	// a reader that fails is an error, not a batch of invalid proofs
+	invalid, err = ctx.VerifyMultiProofsWithReader(proofs[1:3], bytes.NewReader(nil))
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, 0, len(invalid))
+}
@@ -0,0 +1,226 @@
+package fastcommit
+
+import (
+	"errors"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/kzg"
+	"sync"
+)
+
+const (
+	// BytesPerFieldElement is the size of a serialised scalar, big-endian.
+	BytesPerFieldElement = fr.Bytes
+	// BytesPerBlob is the size of a blob, the values of a branch one after another.
+	BytesPerBlob = ScalarSize * BytesPerFieldElement
+	// BytesPerG1 is the size of a compressed G1 point, a commitment or a proof.
+	BytesPerG1 = bls12381.SizeOfG1AffineCompressed
+)
+
+var (
+	ErrNonCanonicalScalar = errors.New("scalar is not canonical, it is not smaller than the group order")
+	ErrInvalidG1Point     = errors.New("invalid G1 point encoding")
+)
+
+// Blob is the serialisation of the values of a branch, as in EIP-4844.
+type Blob [BytesPerBlob]byte
+
+// KZGCommitment is a compressed commitment to a blob.
+type KZGCommitment [BytesPerG1]byte
+
+// KZGProof is a compressed opening proof.
+type KZGProof [BytesPerG1]byte
+
+// Scalar is a big-endian field element, it must be smaller than the group order.
+type Scalar [BytesPerFieldElement]byte
+
+var (
+	eip4844Once sync.Once
+	eip4844Ctx  *KZGContext
+)
+
+// EIP4844Context returns the context of the blob API as specified by EIP-4844: the embedded mainnet
+// setup in bit-reversed order. Its results are those of the consensus clients.
+//
+// The setup is parsed on the first call, it is not affected by SetSetup.
+func EIP4844Context() *KZGContext {
+	eip4844Once.Do(func() {
+		eip4844Ctx = embeddedContext(WithBitReversedOrder())
+	})
+	return eip4844Ctx
+}
+
+// BlobToCommitment is BlobToCommitment of EIP4844Context.
+func BlobToCommitment(blob *Blob) (KZGCommitment, error) {
+	return EIP4844Context().BlobToCommitment(blob)
+}
+
+// ComputeBlobProof is ComputeBlobProof of EIP4844Context.
+func ComputeBlobProof(blob *Blob, commitment KZGCommitment) (KZGProof, error) {
+	return EIP4844Context().ComputeBlobProof(blob, commitment)
+}
+
+// VerifyBlobProof is VerifyBlobProof of EIP4844Context.
+func VerifyBlobProof(blob *Blob, commitment KZGCommitment, proof KZGProof) error {
+	return EIP4844Context().VerifyBlobProof(blob, commitment, proof)
+}
+
+// ComputeKZGProof is ComputeKZGProof of EIP4844Context.
+func ComputeKZGProof(blob *Blob, z Scalar) (KZGProof, Scalar, error) {
+	return EIP4844Context().ComputeKZGProof(blob, z)
+}
+
+// VerifyKZGProof is VerifyKZGProof of EIP4844Context.
+func VerifyKZGProof(commitment KZGCommitment, z, y Scalar, proof KZGProof) error {
+	return EIP4844Context().VerifyKZGProof(commitment, z, y, proof)
+}
+
+// Blob returns the serialisation of the values of the branch.
+func (s *ValueCommit) Blob() Blob {
+	var blob Blob
+	for i := range s.values {
+		b := s.values[i].Bytes()
+		copy(blob[i*BytesPerFieldElement:], b[:])
+	}
+	return blob
+}
+
+// BlobToValueCommit commits to the values serialised in blob, each of which must be canonical.
+func (c *KZGContext) BlobToValueCommit(blob *Blob) (*ValueCommit, error) {
+	s, err := c.blobValues(blob)
+	if nil != err {
+		return nil, err
+	}
+	if s.commit, err = kzg.Commit(s.values, c.srs.Pk, 0); nil != err {
+		return nil, err
+	}
+	return s, nil
+}
+
+// BlobToCommitment returns the commitment to blob, as [blob_to_kzg_commitment].
+//
+// [blob_to_kzg_commitment]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#blob_to_kzg_commitment
+func (c *KZGContext) BlobToCommitment(blob *Blob) (KZGCommitment, error) {
+	s, err := c.BlobToValueCommit(blob)
+	if nil != err {
+		return KZGCommitment{}, err
+	}
+	return s.commit.Bytes(), nil
+}
+
+// ComputeBlobProof returns the proof of blob at the Fiat–Shamir challenge of blob and commitment,
+// as [compute_blob_kzg_proof]. The commitment is not recomputed, it must be the one of blob.
+//
+// [compute_blob_kzg_proof]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#compute_blob_kzg_proof
+func (c *KZGContext) ComputeBlobProof(blob *Blob, commitment KZGCommitment) (KZGProof, error) {
+	s, err := c.blobValues(blob)
+	if nil != err {
+		return KZGProof{}, err
+	}
+	if s.commit, err = parseG1(commitment); nil != err {
+		return KZGProof{}, err
+	}
+	proof, err := s.Proof()
+	if nil != err {
+		return KZGProof{}, err
+	}
+	return proof.Bytes(), nil
+}
+
+// VerifyBlobProof verifies a proof made by ComputeBlobProof, as [verify_blob_kzg_proof].
+//
+// [verify_blob_kzg_proof]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#verify_blob_kzg_proof
+func (c *KZGContext) VerifyBlobProof(blob *Blob, commitment KZGCommitment, proof KZGProof) error {
+	s, err := c.blobValues(blob)
+	if nil != err {
+		return err
+	}
+	if s.commit, err = parseG1(commitment); nil != err {
+		return err
+	}
+	p, err := parseG1(proof)
+	if nil != err {
+		return err
+	}
+	return s.Verify(p)
+}
+
+// ComputeKZGProof returns the proof of blob at z, along with the value y at z, as [compute_kzg_proof].
+//
+// [compute_kzg_proof]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#compute_kzg_proof
+func (c *KZGContext) ComputeKZGProof(blob *Blob, z Scalar) (KZGProof, Scalar, error) {
+	s, err := c.blobValues(blob)
+	if nil != err {
+		return KZGProof{}, Scalar{}, err
+	}
+	point, err := parseScalar(z)
+	if nil != err {
+		return KZGProof{}, Scalar{}, err
+	}
+	proof, err := s.ProofForVal(point)
+	if nil != err {
+		return KZGProof{}, Scalar{}, err
+	}
+	y, err := c.domain.EvaluateLagrangePolynomial(s.values, point)
+	if nil != err {
+		return KZGProof{}, Scalar{}, err
+	}
+	return proof.Bytes(), y.Bytes(), nil
+}
+
+// VerifyKZGProof verifies that the blob committed in commitment takes the value y at z, as [verify_kzg_proof].
+//
+// [verify_kzg_proof]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#verify_kzg_proof
+func (c *KZGContext) VerifyKZGProof(commitment KZGCommitment, z, y Scalar, proof KZGProof) error {
+	commit, err := parseG1(commitment)
+	if nil != err {
+		return err
+	}
+	p, err := parseG1(proof)
+	if nil != err {
+		return err
+	}
+	point, err := parseScalar(z)
+	if nil != err {
+		return err
+	}
+	value, err := parseScalar(y)
+	if nil != err {
+		return err
+	}
+	s := &ValueCommit{commit: commit, ctx: c}
+	return s.VerifyForVal(point, value, p)
+}
+
+// blobValues returns a branch holding the values of blob, without its commitment.
+func (c *KZGContext) blobValues(blob *Blob) (*ValueCommit, error) {
+	vals := make([]fr.Element, ScalarSize)
+	for i := range vals {
+		var b Scalar
+		copy(b[:], blob[i*BytesPerFieldElement:])
+		v, err := parseScalar(b)
+		if nil != err {
+			return nil, err
+		}
+		vals[i] = v
+	}
+	return &ValueCommit{values: vals, ctx: c}, nil
+}
+
+// parseScalar reads a canonical big-endian scalar.
+func parseScalar(b Scalar) (fr.Element, error) {
+	v, err := fr.BigEndian.Element((*[fr.Bytes]byte)(&b))
+	if nil != err {
+		return fr.Element{}, ErrNonCanonicalScalar
+	}
+	return v, nil
+}
+
+// parseG1 reads a compressed G1 point, checking that it is in the prime-order subgroup.
+func parseG1(b [BytesPerG1]byte) (bls12381.G1Affine, error) {
+	var p bls12381.G1Affine
+	if _, err := p.SetBytes(b[:]); nil != err {
+		return p, errors.Join(ErrInvalidG1Point, err)
+	}
+	return p, nil
+}
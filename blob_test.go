@@ -0,0 +1,177 @@
+package fastcommit
+
+import (
+	"bytes"
+	"encoding/hex"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+func randomBlob(t *testing.T) Blob {
+	var blob Blob
+	for i := 0; i < ScalarSize; i++ {
+		var v fr.Element
+		_, err := v.SetRandom()
+		assert.Equal(t, nil, err)
+		b := v.Bytes()
+		copy(blob[i*BytesPerFieldElement:], b[:])
+	}
+	return blob
+}
+
+func randomScalar(t *testing.T) Scalar {
+	var v fr.Element
+	_, err := v.SetRandom()
+	assert.Equal(t, nil, err)
+	return v.Bytes()
+}
+
+// mainnetGoKZGContext returns the context of go-kzg-4844 over the embedded setup, whose lagrange
+// points are in natural order as in the JSON setup of go-kzg-4844.
+func mainnetGoKZGContext(t *testing.T) *gokzg4844.Context {
+	config, err := content.ReadFile("trusted_setup.json")
+	assert.Equal(t, nil, err)
+	setup, err := ParseSetupJSON(bytes.NewReader(config))
+	assert.Equal(t, nil, err)
+
+	var js gokzg4844.JSONTrustedSetup
+	for i, p := range EIP4844Context().monomialG1() {
+		b := p.Bytes()
+		js.SetupG1[i] = "0x" + hex.EncodeToString(b[:])
+	}
+	for i := range setup.LagrangeG1 {
+		b := setup.LagrangeG1[i].Bytes()
+		js.SetupG1Lagrange[i] = "0x" + hex.EncodeToString(b[:])
+	}
+	for i := range setup.G2 {
+		b := setup.G2[i].Bytes()
+		js.SetupG2 = append(js.SetupG2, "0x"+hex.EncodeToString(b[:]))
+	}
+	gctx, err := gokzg4844.NewContext4096(&js)
+	assert.Equal(t, nil, err)
+	return gctx
+}
+
+func TestBlobAPIMainnet(t *testing.T) {
+	gctx := mainnetGoKZGContext(t)
+
+	// the package functions match go-kzg-4844 over the mainnet setup
+	blob := randomBlob(t)
+	gblob := gokzg4844.Blob(blob)
+	commitment, err := BlobToCommitment(&blob)
+	assert.Equal(t, nil, err)
+	gcommitment, err := gctx.BlobToKZGCommitment(gblob, 0)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, KZGCommitment(gcommitment), commitment)
+
+	proof, err := ComputeBlobProof(&blob, commitment)
+	assert.Equal(t, nil, err)
+	gproof, err := gctx.ComputeBlobKZGProof(gblob, gcommitment, 0)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, KZGProof(gproof), proof)
+	assert.Equal(t, nil, VerifyBlobProof(&blob, commitment, proof))
+	assert.Equal(t, nil, gctx.VerifyBlobKZGProof(gblob, gcommitment, gproof))
+
+	z := randomScalar(t)
+	proof, y, err := ComputeKZGProof(&blob, z)
+	assert.Equal(t, nil, err)
+	gproof, gy, err := gctx.ComputeKZGProof(gblob, gokzg4844.Scalar(z), 0)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, KZGProof(gproof), proof)
+	assert.Equal(t, Scalar(gy), y)
+	assert.Equal(t, nil, VerifyKZGProof(commitment, z, y, proof))
+	assert.Equal(t, nil, gctx.VerifyKZGProof(gcommitment, gokzg4844.Scalar(z), gy, gproof))
+
+	// so does the precompile
+	fc, err := EIP4844Context().BlobToValueCommit(&blob)
+	assert.Equal(t, nil, err)
+	input, err := fc.PointEvaluationInput(fc.context().domain.Roots[3])
+	assert.Equal(t, nil, err)
+	_, err = VerifyPointEvaluation(input[:])
+	assert.Equal(t, nil, err)
+	var (
+		pz, py gokzg4844.Scalar
+		pproof gokzg4844.KZGProof
+	)
+	copy(pz[:], input[32:64])
+	copy(py[:], input[64:96])
+	copy(pproof[:], input[144:])
+	assert.Equal(t, nil, gctx.VerifyKZGProof(gcommitment, pz, py, pproof))
+}
+
+func TestBlobAPI(t *testing.T) {
+	// go-kzg-4844 embeds the insecure setup of secret 1337
+	dev, err := NewInsecureDevSetup(ScalarSize, big.NewInt(1337))
+	assert.Equal(t, nil, err)
+	ctx, err := NewKZGContext(&dev.Setup, WithBitReversedOrder())
+	assert.Equal(t, nil, err)
+	gctx, err := gokzg4844.NewContext4096Insecure1337()
+	assert.Equal(t, nil, err)
+
+	for i := 0; i < 2; i++ {
+		blob := randomBlob(t)
+		gblob := gokzg4844.Blob(blob)
+
+		commitment, err := ctx.BlobToCommitment(&blob)
+		assert.Equal(t, nil, err)
+		gcommitment, err := gctx.BlobToKZGCommitment(gblob, 0)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, KZGCommitment(gcommitment), commitment)
+
+		proof, err := ctx.ComputeBlobProof(&blob, commitment)
+		assert.Equal(t, nil, err)
+		gproof, err := gctx.ComputeBlobKZGProof(gblob, gcommitment, 0)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, KZGProof(gproof), proof)
+		assert.Equal(t, nil, ctx.VerifyBlobProof(&blob, commitment, proof))
+		assert.Equal(t, nil, gctx.VerifyBlobKZGProof(gblob, gcommitment, gokzg4844.KZGProof(proof)))
+
+		z := randomScalar(t)
+		proof, y, err := ctx.ComputeKZGProof(&blob, z)
+		assert.Equal(t, nil, err)
+		gproof, gy, err := gctx.ComputeKZGProof(gblob, gokzg4844.Scalar(z), 0)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, KZGProof(gproof), proof)
+		assert.Equal(t, Scalar(gy), y)
+		assert.Equal(t, nil, ctx.VerifyKZGProof(commitment, z, y, proof))
+		assert.Equal(t, nil, gctx.VerifyKZGProof(gcommitment, gokzg4844.Scalar(z), gy, gproof))
+
+		// a wrong value or proof is rejected
+		assert.NotEqual(t, nil, ctx.VerifyKZGProof(commitment, z, randomScalar(t), proof))
+		otherProof, err := ctx.ComputeBlobProof(&blob, commitment)
+		assert.Equal(t, nil, err)
+		assert.NotEqual(t, nil, ctx.VerifyKZGProof(commitment, z, y, otherProof))
+	}
+
+	// the serialisation of a branch is its blob
+	fc := ctx.NewValueCommit(dataCase)
+	blob := fc.Blob()
+	commitment, err := ctx.BlobToCommitment(&blob)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, KZGCommitment(fc.commit.Bytes()), commitment)
+
+	// scalars must be canonical and points in the subgroup
+	modulus := fr.Modulus().FillBytes(make([]byte, BytesPerFieldElement))
+	copy(blob[7*BytesPerFieldElement:], modulus)
+	_, err = ctx.BlobToCommitment(&blob)
+	assert.Equal(t, ErrNonCanonicalScalar, err)
+	var z Scalar
+	copy(z[:], modulus)
+	assert.Equal(t, ErrNonCanonicalScalar, ctx.VerifyKZGProof(commitment, z, Scalar{}, KZGProof(fc.commit.Bytes())))
+	assert.ErrorIs(t, ctx.VerifyKZGProof(KZGCommitment{0xff}, Scalar{}, Scalar{}, KZGProof{}), ErrInvalidG1Point)
+
+	// the package functions run over the mainnet setup
+	blob = randomBlob(t)
+	commitment, err = BlobToCommitment(&blob)
+	assert.Equal(t, nil, err)
+	proof, err := ComputeBlobProof(&blob, commitment)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nil, VerifyBlobProof(&blob, commitment, proof))
+	z = randomScalar(t)
+	proof, y, err := ComputeKZGProof(&blob, z)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nil, VerifyKZGProof(commitment, z, y, proof))
+}
@@ -0,0 +1,262 @@
+package fastcommit
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	crateKzg "github/yyjia/fastcommit/crateKzg/kzg"
+	"io"
+	"math/big"
+	"math/bits"
+)
+
+var ErrInvalidContribution = errors.New("invalid powers-of-tau contribution")
+
+// DomSepContribution is a Domain Separator for hashing a contribution into the G1 point its proof of knowledge signs.
+const DomSepContribution = "FASTCOMMIT_POT_CONTRIBUTION_V1_"
+
+// PowersOfTau is a setup in monomial form, the state of a powers-of-tau ceremony.
+//
+// Every contribution multiplies the secret τ by a fresh secret x, which the contributor then
+// forgets. The setup is secure as long as one contributor did.
+type PowersOfTau struct {
+	// G1 are the powers [τ^i]G₁
+	G1 []bls12381.G1Affine
+	// G2 are the powers [τ^i]G₂
+	G2 []bls12381.G2Affine
+}
+
+// ContributionProof shows that a contribution multiplied τ by a secret x: e([τx]G₁, G₂) = e([τ]G₁, [x]G₂).
+// A chain of proofs links the setup a ceremony starts from to its result.
+//
+// PoK proves that the contributor knows x: it is [x]H for H the hash of the previous running product,
+// TauG1 and PotPubkey, checked by e(PoK, G₂) = e(H, [x]G₂). Without it a participant could submit a
+// rescaled copy of another contribution.
+type ContributionProof struct {
+	// TauG1 is [τ]G₁ after the contribution, the running product of the secrets
+	TauG1 bls12381.G1Affine
+	// PotPubkey is [x]G₂, for the secret x of the contribution
+	PotPubkey bls12381.G2Affine
+	// PoK is [x]H, a BLS signature of the contribution by its secret
+	PoK bls12381.G1Affine
+}
+
+// NewPowersOfTau returns the setup a ceremony starts from: width G1 powers and numG2 G2 powers of τ = 1.
+// The width must be a power of two, at least 2.
+func NewPowersOfTau(width uint64, numG2 int) (*PowersOfTau, error) {
+	if width < 2 || bits.OnesCount64(width) != 1 {
+		return nil, fmt.Errorf("%w: width %d is not a power of two", ErrInvalidSetup, width)
+	}
+	if numG2 < 2 {
+		return nil, crateKzg.ErrMinSRSSize
+	}
+	_, _, g1, g2 := bls12381.Generators()
+	p := &PowersOfTau{
+		G1: make([]bls12381.G1Affine, width),
+		G2: make([]bls12381.G2Affine, numG2),
+	}
+	for i := range p.G1 {
+		p.G1[i] = g1
+	}
+	for i := range p.G2 {
+		p.G2[i] = g2
+	}
+	return p, nil
+}
+
+// ParsePowersOfTauJSON reads a setup in the JSON format of trusted_setup.json. When the file has
+// no monomial G1 points, they are computed from the lagrange points.
+//
+// The points are not checked, see Validate.
+func ParsePowersOfTauJSON(r io.Reader) (*PowersOfTau, error) {
+	s, monomial, err := parseSetupJSON(r)
+	if nil != err {
+		return nil, err
+	}
+	p := &PowersOfTau{G2: s.G2}
+	if len(monomial) == 0 {
+		p.G1 = crateKzg.NewDomain(uint64(len(s.LagrangeG1))).FftG1(s.LagrangeG1)
+	} else if p.G1, err = parseG1PointsNoSubgroupCheck(monomial); nil != err {
+		return nil, err
+	}
+	if len(p.G1) != len(s.LagrangeG1) {
+		return nil, fmt.Errorf("%w: %d monomial points for %d lagrange points", ErrInvalidSetup, len(p.G1), len(s.LagrangeG1))
+	}
+	if err := p.checkSizes(); nil != err {
+		return nil, err
+	}
+	return p, nil
+}
+
+// WriteJSON writes the setup in the format of trusted_setup.json, with both the monomial and the lagrange G1 points.
+func (p *PowersOfTau) WriteJSON(w io.Writer) error {
+	return writeSetupJSON(w, p.Setup(), p.G1)
+}
+
+// Setup returns the setup in lagrange form, the inverse FFT of the G1 powers.
+func (p *PowersOfTau) Setup() *Setup {
+	return &Setup{
+		G1:         p.G1[0],
+		LagrangeG1: crateKzg.NewDomain(uint64(len(p.G1))).IfftG1(p.G1),
+		G2:         p.G2,
+	}
+}
+
+// Contribute re-randomises the setup with a fresh secret from crypto/rand, which is forgotten once
+// the contribution is made.
+func (p *PowersOfTau) Contribute() (*PowersOfTau, ContributionProof, error) {
+	return p.ContributeWithReader(rand.Reader)
+}
+
+// ContributeWithReader re-randomises the setup with a secret read from reader.
+func (p *PowersOfTau) ContributeWithReader(reader io.Reader) (*PowersOfTau, ContributionProof, error) {
+	if err := p.checkSizes(); nil != err {
+		return nil, ContributionProof{}, err
+	}
+	var buf [64]byte
+	if _, err := io.ReadFull(reader, buf[:]); nil != err {
+		return nil, ContributionProof{}, err
+	}
+	var x fr.Element
+	x.SetBytes(buf[:])
+	buf = [64]byte{}
+	defer x.SetZero()
+	if x.IsZero() {
+		return nil, ContributionProof{}, crateKzg.ErrZeroSecret
+	}
+
+	next := &PowersOfTau{
+		G1: make([]bls12381.G1Affine, len(p.G1)),
+		G2: make([]bls12381.G2Affine, len(p.G2)),
+	}
+	// G1[i] and G2[i] are multiplied by x^i
+	power := fr.One()
+	var e big.Int
+	for i := 0; i < len(next.G1) || i < len(next.G2); i++ {
+		power.BigInt(&e)
+		if i < len(next.G1) {
+			next.G1[i].ScalarMultiplication(&p.G1[i], &e)
+		}
+		if i < len(next.G2) {
+			next.G2[i].ScalarMultiplication(&p.G2[i], &e)
+		}
+		power.Mul(&power, &x)
+	}
+
+	proof := ContributionProof{TauG1: next.G1[1]}
+	_, _, _, g2 := bls12381.Generators()
+	x.BigInt(&e)
+	defer e.SetUint64(0)
+	proof.PotPubkey.ScalarMultiplication(&g2, &e)
+	h, err := contributionHash(&p.G1[1], &proof)
+	if nil != err {
+		return nil, ContributionProof{}, err
+	}
+	proof.PoK.ScalarMultiplication(&h, &e)
+	power.SetZero()
+	return next, proof, nil
+}
+
+// Validate checks that the points are in their prime-order subgroups and are the powers of one
+// secret τ ≠ 0, starting from the generators.
+func (p *PowersOfTau) Validate() error {
+	if err := p.checkSizes(); nil != err {
+		return err
+	}
+	_, _, g1, g2 := bls12381.Generators()
+	if !p.G1[0].Equal(&g1) || !p.G2[0].Equal(&g2) {
+		return fmt.Errorf("%w: the first powers are not the generators", ErrInvalidSetup)
+	}
+	if p.G1[1].IsInfinity() {
+		return fmt.Errorf("%w: the secret is zero", ErrInvalidSetup)
+	}
+	if err := checkPointSubgroups("monomial", p.G1, p.G2); nil != err {
+		return err
+	}
+	return checkPowers(p.G1, p.G2)
+}
+
+// checkSizes checks that the setup has a power of two G1 points, at least 2, and at least two G2 points.
+func (p *PowersOfTau) checkSizes() error {
+	if len(p.G2) < 2 {
+		return crateKzg.ErrMinSRSSize
+	}
+	if len(p.G1) < 2 || bits.OnesCount(uint(len(p.G1))) != 1 {
+		return ErrInvalidSetup
+	}
+	return nil
+}
+
+// VerifyContributions checks that final results from start through the contributions proved by proofs,
+// in order, and that final is a valid setup.
+func VerifyContributions(start, final *PowersOfTau, proofs []ContributionProof) error {
+	if start == nil || final == nil {
+		return fmt.Errorf("%w: missing setup", ErrInvalidContribution)
+	}
+	if err := start.checkSizes(); nil != err {
+		return err
+	}
+	if len(start.G1) != len(final.G1) || len(start.G2) != len(final.G2) {
+		return fmt.Errorf("%w: the setups have different sizes", ErrInvalidContribution)
+	}
+
+	_, _, _, g2 := bls12381.Generators()
+	prev := start.G1[1]
+	for i := range proofs {
+		proof := &proofs[i]
+		if proof.TauG1.IsInfinity() || !proof.TauG1.IsOnCurve() || !proof.TauG1.IsInSubGroup() {
+			return fmt.Errorf("%w: the running product of contribution %d is not in the G1 subgroup", ErrInvalidContribution, i)
+		}
+		if proof.PotPubkey.IsInfinity() || !proof.PotPubkey.IsOnCurve() || !proof.PotPubkey.IsInSubGroup() {
+			return fmt.Errorf("%w: the public key of contribution %d is not in the G2 subgroup", ErrInvalidContribution, i)
+		}
+
+		// e([τx]G₁, G₂) = e([τ]G₁, [x]G₂)
+		var negPrev bls12381.G1Affine
+		negPrev.Neg(&prev)
+		ok, err := bls12381.PairingCheck([]bls12381.G1Affine{proof.TauG1, negPrev}, []bls12381.G2Affine{g2, proof.PotPubkey})
+		if nil != err {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("%w: contribution %d does not extend the running product", ErrInvalidContribution, i)
+		}
+
+		// e([x]H, G₂) = e(H, [x]G₂)
+		if proof.PoK.IsInfinity() || !proof.PoK.IsOnCurve() || !proof.PoK.IsInSubGroup() {
+			return fmt.Errorf("%w: the proof of knowledge of contribution %d is not in the G1 subgroup", ErrInvalidContribution, i)
+		}
+		h, err := contributionHash(&prev, proof)
+		if nil != err {
+			return err
+		}
+		var negH bls12381.G1Affine
+		negH.Neg(&h)
+		ok, err = bls12381.PairingCheck([]bls12381.G1Affine{proof.PoK, negH}, []bls12381.G2Affine{g2, proof.PotPubkey})
+		if nil != err {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("%w: contribution %d has an invalid proof of knowledge", ErrInvalidContribution, i)
+		}
+		prev = proof.TauG1
+	}
+
+	if !final.G1[1].Equal(&prev) {
+		return fmt.Errorf("%w: the final setup does not match the last contribution", ErrInvalidContribution)
+	}
+	return final.Validate()
+}
+
+// contributionHash hashes the running product before a contribution, and the running product and public key
+// after it, to the G1 point its proof of knowledge signs.
+func contributionHash(prev *bls12381.G1Affine, proof *ContributionProof) (bls12381.G1Affine, error) {
+	prevBytes, tauBytes, pubkeyBytes := prev.Bytes(), proof.TauG1.Bytes(), proof.PotPubkey.Bytes()
+	msg := make([]byte, 0, len(prevBytes)+len(tauBytes)+len(pubkeyBytes))
+	msg = append(msg, prevBytes[:]...)
+	msg = append(msg, tauBytes[:]...)
+	msg = append(msg, pubkeyBytes[:]...)
+	return bls12381.HashToG1(msg, []byte(DomSepContribution))
+}
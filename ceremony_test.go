@@ -0,0 +1,67 @@
+package fastcommit
+
+import (
+	"bytes"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCeremony(t *testing.T) {
+	start, err := NewPowersOfTau(16, 4)
+	assert.Equal(t, nil, err)
+
+	first, p1, err := start.Contribute()
+	assert.Equal(t, nil, err)
+	second, p2, err := first.ContributeWithReader(bytes.NewReader(bytes.Repeat([]byte{7}, 64)))
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, nil, VerifyContributions(start, second, []ContributionProof{p1, p2}))
+	assert.Equal(t, nil, VerifyContributions(first, second, []ContributionProof{p2}))
+	assert.Equal(t, nil, second.Setup().Validate())
+	assert.Equal(t, nil, second.Setup().ValidateMonomial(second.G1))
+
+	// the chain must be complete and in order
+	assert.ErrorIs(t, VerifyContributions(start, second, []ContributionProof{p2, p1}), ErrInvalidContribution)
+	assert.ErrorIs(t, VerifyContributions(start, second, []ContributionProof{p1}), ErrInvalidContribution)
+	assert.ErrorIs(t, VerifyContributions(start, second, []ContributionProof{{TauG1: p2.TauG1, PotPubkey: p1.PotPubkey}}), ErrInvalidContribution)
+
+	// each contribution must prove knowledge of its own secret
+	forged := p2
+	forged.PoK = p1.PoK
+	assert.ErrorIs(t, VerifyContributions(first, second, []ContributionProof{forged}), ErrInvalidContribution)
+	forged.PoK = bls12381.G1Affine{}
+	assert.ErrorIs(t, VerifyContributions(first, second, []ContributionProof{forged}), ErrInvalidContribution)
+
+	// the start of the chain must be a setup of the same size
+	assert.ErrorIs(t, VerifyContributions(&PowersOfTau{G1: start.G1[:1], G2: start.G2}, second, []ContributionProof{p1, p2}), ErrInvalidSetup)
+	assert.ErrorIs(t, VerifyContributions(&PowersOfTau{G1: start.G1[:8], G2: start.G2}, second, []ContributionProof{p1, p2}), ErrInvalidContribution)
+	assert.ErrorIs(t, VerifyContributions(nil, second, nil), ErrInvalidContribution)
+
+	// the final powers must be consistent with each other
+	tampered := &PowersOfTau{G1: append(second.G1[:0:0], second.G1...), G2: second.G2}
+	tampered.G1[2], tampered.G1[3] = tampered.G1[3], tampered.G1[2]
+	assert.ErrorIs(t, VerifyContributions(start, tampered, []ContributionProof{p1, p2}), ErrInvalidSetup)
+
+	// the result is exported in the trusted_setup.json format
+	var buf bytes.Buffer
+	assert.Equal(t, nil, second.WriteJSON(&buf))
+	fromJSON, err := ParsePowersOfTauJSON(bytes.NewReader(buf.Bytes()))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, second, fromJSON)
+	setup, err := ParseSetupJSONValidated(&buf)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, second.Setup(), setup)
+
+	_, _, err = start.ContributeWithReader(bytes.NewReader(make([]byte, 64)))
+	assert.NotEqual(t, nil, err)
+
+	// a single G1 point has no τ to contribute to
+	single := &PowersOfTau{G1: start.G1[:1], G2: start.G2}
+	_, _, err = single.Contribute()
+	assert.ErrorIs(t, err, ErrInvalidSetup)
+	buf.Reset()
+	assert.Equal(t, nil, single.WriteJSON(&buf))
+	_, err = ParsePowersOfTauJSON(&buf)
+	assert.ErrorIs(t, err, ErrInvalidSetup)
+}
@@ -0,0 +1,168 @@
+package fastcommit
+
+import (
+	"errors"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+const (
+	// CodeChunkSize is the number of code bytes carried by one chunk.
+	CodeChunkSize = 31
+
+	push1  = 0x60
+	push32 = 0x7f
+)
+
+var (
+	ErrChunkOutOfRange = errors.New("code chunk out of range")
+	ErrChunkMismatch   = errors.New("code chunk does not match the committed value")
+)
+
+// CodeChunk is one slot of contract code, Verkle-style: the first byte
+// holds the number of leading bytes that are push-data of an instruction
+// started in an earlier chunk, the other 31 bytes are the code itself.
+//
+// The first byte is never larger than 31, so the chunk read as a big-endian
+// number is always below the scalar field modulus.
+type CodeChunk [32]byte
+
+// PushDataOffset returns the number of leading code bytes that are push-data.
+func (c CodeChunk) PushDataOffset() int {
+	return int(c[0])
+}
+
+// Code returns the 31 code bytes of the chunk.
+func (c CodeChunk) Code() []byte {
+	return c[1:]
+}
+
+// Element returns the field element stored in the slot of the chunk.
+func (c CodeChunk) Element() fr.Element {
+	return *new(fr.Element).SetBytes(c[:])
+}
+
+// ChunkifyCode splits code into 31-byte chunks, prefixed with their push-data offset.
+// The last chunk is padded with zeroes.
+//
+// [EIP-6800]: https://eips.ethereum.org/EIPS/eip-6800
+func ChunkifyCode(code []byte) []CodeChunk {
+	numChunks := (len(code) + CodeChunkSize - 1) / CodeChunkSize
+	padded := make([]byte, numChunks*CodeChunkSize)
+	copy(padded, code)
+
+	// pushData[i] is the number of push-data bytes left, starting at position i
+	pushData := make([]int, len(padded))
+	for pos := 0; pos < len(code); {
+		op := code[pos]
+		pos++
+		if op < push1 || op > push32 {
+			continue
+		}
+		n := int(op-push1) + 1
+		for x := 0; x < n && pos+x < len(pushData); x++ {
+			pushData[pos+x] = n - x
+		}
+		pos += n
+	}
+
+	chunks := make([]CodeChunk, numChunks)
+	for i := range chunks {
+		pos := i * CodeChunkSize
+		offset := pushData[pos]
+		if offset > CodeChunkSize {
+			offset = CodeChunkSize
+		}
+		chunks[i][0] = byte(offset)
+		copy(chunks[i][1:], padded[pos:pos+CodeChunkSize])
+	}
+	return chunks
+}
+
+// UpdateCode stores the chunks of code in consecutive slots starting at codeIndex, in the branches of DefaultKZGContext.
+func UpdateCode(codeIndex int, code []byte) error {
+	return DefaultKZGContext().UpdateCode(codeIndex, code)
+}
+
+// UpdateCode stores the chunks of code in consecutive slots starting at codeIndex.
+func (c *KZGContext) UpdateCode(codeIndex int, code []byte) error {
+	for i, chunk := range ChunkifyCode(code) {
+		if err := c.Updates(codeIndex+i, chunk.Element()); nil != err {
+			return err
+		}
+	}
+	return nil
+}
+
+// CodeProof opens a selection of the chunks of one contract.
+//
+// Every chunk is opened on its own against the branch holding its slot,
+// so chunks of a contract may span more than one branch.
+type CodeProof struct {
+	Chunks []int
+	Values []CodeChunk
+	Proofs []bls12381.G1Affine
+}
+
+// ProofForCodeChunks is ProofForCodeChunks of DefaultKZGContext.
+func ProofForCodeChunks(codeIndex int, chunks []int) (*CodeProof, error) {
+	return DefaultKZGContext().ProofForCodeChunks(codeIndex, chunks)
+}
+
+// ProofForCodeChunks opens the chunks numbered by chunks of the code stored at codeIndex.
+func (c *KZGContext) ProofForCodeChunks(codeIndex int, chunks []int) (*CodeProof, error) {
+	vals, proofs, err := c.proofForSlots(chunkSlots(codeIndex, chunks))
+	if nil != err {
+		return nil, chunkError(err)
+	}
+
+	res := &CodeProof{
+		Chunks: chunks,
+		Values: make([]CodeChunk, len(chunks)),
+		Proofs: proofs,
+	}
+	for i := range vals {
+		res.Values[i] = vals[i].Bytes()
+	}
+	return res, nil
+}
+
+// Verify is VerifyCodeProof of DefaultKZGContext.
+func (p *CodeProof) Verify(codeIndex int, commits map[int]bls12381.G1Affine) error {
+	return DefaultKZGContext().VerifyCodeProof(p, codeIndex, commits)
+}
+
+// VerifyCodeProof checks the chunks of p against the commitments of the branches, keyed by branch number.
+func (c *KZGContext) VerifyCodeProof(p *CodeProof, codeIndex int, commits map[int]bls12381.G1Affine) error {
+	if len(p.Values) != len(p.Chunks) || len(p.Proofs) != len(p.Chunks) {
+		return ErrChunkMismatch
+	}
+	vals := make([]fr.Element, len(p.Values))
+	for i := range p.Values {
+		if p.Values[i][0] > CodeChunkSize {
+			return ErrChunkMismatch
+		}
+		vals[i] = p.Values[i].Element()
+	}
+	return chunkError(c.verifySlots(chunkSlots(codeIndex, p.Chunks), vals, p.Proofs, commits))
+}
+
+// chunkError reports the slot errors of the shared helpers with the errors of code chunks.
+func chunkError(err error) error {
+	if err == ErrSlotOutOfRange {
+		return ErrChunkOutOfRange
+	}
+	return err
+}
+
+func chunkSlots(codeIndex int, chunks []int) []int {
+	slots := make([]int, len(chunks))
+	for i, c := range chunks {
+		if c < 0 {
+			slots[i] = -1
+			continue
+		}
+		slots[i] = codeIndex + c
+	}
+	return slots
+}
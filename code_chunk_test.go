@@ -0,0 +1,61 @@
+package fastcommit
+
+import (
+	"bytes"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestChunkifyCode(t *testing.T) {
+	// PUSH4 at byte 29 spills three bytes of push-data into the second chunk
+	code := make([]byte, 40)
+	code[29] = push1 + 3
+	code[34] = push32
+
+	chunks := ChunkifyCode(code)
+	assert.Equal(t, 2, len(chunks))
+	assert.Equal(t, 0, chunks[0].PushDataOffset())
+	assert.Equal(t, 3, chunks[1].PushDataOffset())
+	assert.True(t, bytes.Equal(code[:CodeChunkSize], chunks[0].Code()))
+	assert.True(t, bytes.Equal(code[CodeChunkSize:], chunks[1].Code()[:len(code)-CodeChunkSize]))
+
+	// a PUSH32 covering a whole chunk is capped to the chunk size
+	code = make([]byte, 70)
+	code[30] = push32
+	chunks = ChunkifyCode(code)
+	assert.Equal(t, 3, len(chunks))
+	assert.Equal(t, CodeChunkSize, chunks[1].PushDataOffset())
+	assert.Equal(t, 1, chunks[2].PushDataOffset())
+}
+
+func TestCodeProof_Verify(t *testing.T) {
+	code := make([]byte, 200)
+	for i := range code {
+		code[i] = byte(i)
+	}
+	// start close to a branch boundary so the code spans two branches
+	codeIndex := 2*POLY_SIZE - 3
+	err := UpdateCode(codeIndex, code)
+	assert.Equal(t, nil, err)
+
+	proof, err := ProofForCodeChunks(codeIndex, []int{0, 4, 6})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, ChunkifyCode(code)[4], proof.Values[1])
+
+	commits := map[int]bls12381.G1Affine{
+		1: DefaultKZGContext().branchs[1].commit,
+		2: DefaultKZGContext().branchs[2].commit,
+	}
+	err = proof.Verify(codeIndex, commits)
+	assert.Equal(t, nil, err)
+
+	proof.Values[1][5]++
+	err = proof.Verify(codeIndex, commits)
+	assert.NotEqual(t, nil, err)
+
+	_, err = ProofForCodeChunks(codeIndex, []int{-1})
+	assert.Equal(t, ErrChunkOutOfRange, err)
+	proof.Values = proof.Values[:1]
+	assert.Equal(t, ErrChunkMismatch, proof.Verify(codeIndex, commits))
+}
@@ -0,0 +1,113 @@
+package fastcommit
+
+import (
+	"crypto/sha256"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// DomSepCommitMap is a Domain Separator for hashing a branch commitment into its parent slot.
+const DomSepCommitMap = "FASTCOMMIT_COMMIT_TO_FIELD_V1_"
+
+// CommitmentMapper maps the commitment of a branch to the scalar stored in its parent slot.
+//
+// level is the level of the branch the commitment belongs to, 0 being the leaf branches.
+// The tree and the verifier must use the same mapper.
+type CommitmentMapper interface {
+	MapToField(level int, c *bls12381.G1Affine) fr.Element
+	// ID names the mapper and its parameters, it is bound into the root hash.
+	ID() []byte
+}
+
+// LegacyMapper hashes the compressed commitment with SHA-256 and no domain separation.
+//
+// This is how parent slots were filled before mappers were configurable.
+type LegacyMapper struct{}
+
+func (LegacyMapper) MapToField(_ int, c *bls12381.G1Affine) fr.Element {
+	b := c.Bytes()
+	return hashToBLSField(b[:])
+}
+
+func (LegacyMapper) ID() []byte {
+	return []byte("legacy")
+}
+
+// TaggedMapper hashes the compressed commitment with SHA-256, prefixed with
+// DomSepCommitMap, a tag naming the tree and the level of the branch.
+//
+// TaggedMapper with no tag is the mapper of a KZGContext unless WithCommitmentMapper is given.
+type TaggedMapper struct {
+	Tag []byte
+}
+
+func (m TaggedMapper) MapToField(level int, c *bls12381.G1Affine) fr.Element {
+	b := c.Bytes()
+	h := hash256(
+		[]byte(DomSepCommitMap),
+		u64ToByteArray16(uint64(len(m.Tag))),
+		m.Tag,
+		u64ToByteArray16(uint64(level)),
+		b[:],
+	)
+	return *new(fr.Element).SetBytes(h[:])
+}
+
+func (m TaggedMapper) ID() []byte {
+	return append([]byte("tagged-sha256/"), m.Tag...)
+}
+
+// XCoordMapper takes the x-coordinate of the commitment, reduced modulo the scalar field.
+//
+// It is cheaper than hashing, but carries no domain separation between levels or trees.
+type XCoordMapper struct{}
+
+func (XCoordMapper) MapToField(_ int, c *bls12381.G1Affine) fr.Element {
+	x := c.X.Bytes()
+	return *new(fr.Element).SetBytes(x[:])
+}
+
+func (XCoordMapper) ID() []byte {
+	return []byte("x-coordinate")
+}
+
+// WithCommitmentMapper sets the map used to fill and check parent slots.
+// The tree and its verifiers must be built with the same mapper.
+func WithCommitmentMapper(m CommitmentMapper) ContextOption {
+	return func(c *KZGContext) {
+		c.mapper = m
+	}
+}
+
+// CommitmentToField is CommitmentToField of DefaultKZGContext.
+func CommitmentToField(level int, commit *bls12381.G1Affine) fr.Element {
+	return DefaultKZGContext().CommitmentToField(level, commit)
+}
+
+// CommitmentToField returns the scalar stored in the parent slot of the branch
+// at level whose commitment is commit.
+func (c *KZGContext) CommitmentToField(level int, commit *bls12381.G1Affine) fr.Element {
+	return c.mapper.MapToField(level, commit)
+}
+
+// ParentBranches commits to the level above children, which are the branches at level:
+// slot i of the parents holds the commitment of children[i], mapped by CommitmentToField.
+func (c *KZGContext) ParentBranches(level int, children []*ValueCommit) []*ValueCommit {
+	parents := make([]*ValueCommit, 0, (len(children)+POLY_SIZE-1)/POLY_SIZE)
+	for start := 0; start < len(children); start += POLY_SIZE {
+		data := make([]Account, len(children)-start)
+		if len(data) > POLY_SIZE {
+			data = data[:POLY_SIZE]
+		}
+		for i := range data {
+			data[i].state = c.CommitmentToField(level, &children[start+i].commit)
+		}
+		parents = append(parents, c.NewValueCommit(data))
+	}
+	return parents
+}
+
+// mapperID is the digest of the ID of the mapper of c.
+func (c *KZGContext) mapperID() [32]byte {
+	return sha256.Sum256(c.mapper.ID())
+}
@@ -0,0 +1,45 @@
+package fastcommit
+
+import (
+	"bytes"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCommitmentMapper(t *testing.T) {
+	_, _, g1, _ := bls12381.Generators()
+	b := g1.Bytes()
+	assert.Equal(t, hashToBLSField(b[:]), LegacyMapper{}.MapToField(1, &g1))
+
+	a := TaggedMapper{Tag: []byte("a")}
+	v0 := a.MapToField(0, &g1)
+	v1 := a.MapToField(1, &g1)
+	assert.NotEqual(t, v0, v1)
+	assert.NotEqual(t, v0, TaggedMapper{Tag: []byte("b")}.MapToField(0, &g1))
+
+	x := XCoordMapper{}.MapToField(0, &g1)
+	assert.False(t, x.IsZero())
+}
+
+func TestWithCommitmentMapper(t *testing.T) {
+	config, err := content.ReadFile("trusted_setup.json")
+	assert.Equal(t, nil, err)
+	setup, err := ParseSetupJSON(bytes.NewReader(config))
+	assert.Equal(t, nil, err)
+	ctx, err := NewKZGContext(setup, WithCommitmentMapper(TaggedMapper{Tag: []byte("devnet")}))
+	assert.Equal(t, nil, err)
+
+	// the default mapper is domain separated
+	_, _, g1, _ := bls12381.Generators()
+	assert.Equal(t, TaggedMapper{}.MapToField(2, &g1), CommitmentToField(2, &g1))
+	assert.NotEqual(t, rootHash(DefaultKZGContext(), g1), rootHash(ctx, g1))
+	assert.Equal(t, TaggedMapper{Tag: []byte("devnet")}.MapToField(2, &g1), ctx.CommitmentToField(2, &g1))
+
+	// and fills the parent slots
+	children := []*ValueCommit{ctx.NewValueCommit(dataCase), ctx.NewValueCommit(nil)}
+	parents := ctx.ParentBranches(1, children)
+	assert.Equal(t, 1, len(parents))
+	assert.Equal(t, ctx.CommitmentToField(1, &children[1].commit), parents[0].values[1])
+	assert.True(t, parents[0].values[2].IsZero())
+}
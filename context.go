@@ -0,0 +1,219 @@
+package fastcommit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/kzg"
+	crateKzg "github/yyjia/fastcommit/crateKzg/kzg"
+	"sync"
+	"sync/atomic"
+)
+
+// KZGContext holds a trusted setup and everything derived from it: the domain, the keys,
+// and the precomputations of FK20, update keys and pairing lines.
+//
+// The precomputations are made on first use. Branches, proofs and verifiers take the context
+// they were built with; those built without one use DefaultKZGContext.
+type KZGContext struct {
+	srs     kzg.SRS
+	domain  *crateKzg.Domain
+	setupG2 []bls12381.G2Affine
+	// bitReversed is set when the lagrange points and the roots are in bit-reversed order
+	bitReversed bool
+	// mapper fills the parent slots with the commitments of the branches below
+	mapper CommitmentMapper
+
+	srsIDOnce sync.Once
+	srsID     [32]byte
+
+	monomialOnce sync.Once
+	monomial     []bls12381.G1Affine
+
+	fk20Once sync.Once
+	fk20     *crateKzg.FK20
+	fk20Err  error
+
+	updateKeysOnce sync.Once
+	updateKeys     *crateKzg.UpdateKeys
+
+	preparedKeyOnce sync.Once
+	preparedKey     *crateKzg.PreparedOpeningKey
+
+	// branchs, branchs1 and branchs2 are the levels of the tree, from the leaves up to the root.
+	// Updates fills the leaves, slot i being in branchs[i/POLY_SIZE], and UpdatesRoot commits the levels above.
+	branchs, branchs1, branchs2 []*ValueCommit
+	// preimages are the preimages of the slots set by UpdatePreimage
+	preimages *PreimageStore
+}
+
+var (
+	defaultOnce sync.Once
+	// defaultCtx is set by the first call to DefaultKZGContext, or by SetSetup
+	defaultCtx atomic.Pointer[KZGContext]
+)
+
+// ContextOption configures a KZGContext.
+type ContextOption func(*KZGContext)
+
+// WithBitReversedOrder puts the slots of every branch in bit-reversed order: slot i is committed at the
+// bit-reversal of the i'th power of the root of unity, as EIP-4844 does. A branch then has the commitment
+// that consensus clients compute for the blob of its values.
+//
+// The ordering changes every commitment, proof and root hash, trees must not mix contexts of both orderings.
+func WithBitReversedOrder() ContextOption {
+	return func(c *KZGContext) {
+		c.bitReversed = true
+	}
+}
+
+// NewKZGContext returns a context over setup, which must have the width of the tree.
+func NewKZGContext(setup *Setup, opts ...ContextOption) (*KZGContext, error) {
+	if err := setup.checkSizes(); nil != err {
+		return nil, err
+	}
+	if len(setup.LagrangeG1) != ScalarSize {
+		return nil, ErrSetupSize
+	}
+	c := &KZGContext{
+		srs: kzg.SRS{
+			Vk: kzg.VerifyingKey{G2: [2]bls12381.G2Affine{setup.G2[0], setup.G2[1]}, G1: setup.G1},
+			Pk: kzg.ProvingKey{G1: setup.LagrangeG1},
+		},
+		domain:    crateKzg.NewDomain(uint64(len(setup.LagrangeG1))),
+		setupG2:   setup.G2,
+		mapper:    TaggedMapper{},
+		preimages: NewPreimageStore(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.bitReversed {
+		// the points are reversed on a copy, the setup may be shared with other contexts
+		ck := &crateKzg.CommitKey{G1: make([]bls12381.G1Affine, len(setup.LagrangeG1))}
+		copy(ck.G1, setup.LagrangeG1)
+		ck.ReversePoints()
+		c.srs.Pk.G1 = ck.G1
+		c.domain.ReverseRoots()
+	}
+	return c, nil
+}
+
+// NewKZGContextFromFile returns a context over the setup in a file, in either the JSON or the binary format.
+func NewKZGContextFromFile(path string, opts ...ContextOption) (*KZGContext, error) {
+	setup, err := LoadSetupFile(path)
+	if nil != err {
+		return nil, err
+	}
+	return NewKZGContext(setup, opts...)
+}
+
+// DefaultKZGContext returns the context of the embedded trusted_setup.json, the mainnet EIP-4844 setup.
+//
+// The setup is parsed on the first call, so importing the package costs nothing.
+func DefaultKZGContext() *KZGContext {
+	if ctx := defaultCtx.Load(); ctx != nil {
+		return ctx
+	}
+	defaultOnce.Do(func() {
+		// a setup installed by SetSetup in the meantime is kept
+		defaultCtx.CompareAndSwap(nil, embeddedContext())
+	})
+	return defaultCtx.Load()
+}
+
+// embeddedContext returns a context over the embedded trusted_setup.json, it panics if the file cannot be loaded.
+func embeddedContext(opts ...ContextOption) *KZGContext {
+	config, err := content.ReadFile("trusted_setup.json")
+	if err != nil {
+		panic(err)
+	}
+	setup, err := ParseSetupJSON(bytes.NewReader(config))
+	if err != nil {
+		panic(err)
+	}
+	ctx, err := NewKZGContext(setup, opts...)
+	if nil != err {
+		panic(err)
+	}
+	return ctx
+}
+
+// orDefault returns c, or DefaultKZGContext when c is nil.
+func (c *KZGContext) orDefault() *KZGContext {
+	if c == nil {
+		return DefaultKZGContext()
+	}
+	return c
+}
+
+// commitKey returns the lagrange points of the setup.
+func (c *KZGContext) commitKey() *crateKzg.CommitKey {
+	return &crateKzg.CommitKey{G1: c.srs.Pk.G1}
+}
+
+// SRSID returns a digest of the trusted setup, so that roots computed
+// over different setups never compare equal.
+func (c *KZGContext) SRSID() [32]byte {
+	c.srsIDOnce.Do(func() {
+		h := sha256.New()
+		g1 := c.srs.Vk.G1.Bytes()
+		h.Write(g1[:])
+		for i := range c.srs.Vk.G2 {
+			g2 := c.srs.Vk.G2[i].Bytes()
+			h.Write(g2[:])
+		}
+		for i := range c.srs.Pk.G1 {
+			p := c.srs.Pk.G1[i].Bytes()
+			h.Write(p[:])
+		}
+		h.Sum(c.srsID[:0])
+	})
+	return c.srsID
+}
+
+// monomialG1 returns the G1 points of the setup in monomial form, [α^i]G₁.
+//
+// The setup only holds the lagrange points, the monomial points are their FFT in natural order.
+func (c *KZGContext) monomialG1() []bls12381.G1Affine {
+	c.monomialOnce.Do(func() {
+		lagrange := c.srs.Pk.G1
+		if c.bitReversed {
+			ck := &crateKzg.CommitKey{G1: make([]bls12381.G1Affine, len(lagrange))}
+			copy(ck.G1, lagrange)
+			ck.ReversePoints()
+			lagrange = ck.G1
+		}
+		c.monomial = c.domain.FftG1(lagrange)
+	})
+	return c.monomial
+}
+
+// getFK20 builds the FK20 precomputation on first use, it takes a few seconds.
+func (c *KZGContext) getFK20() (*crateKzg.FK20, error) {
+	c.fk20Once.Do(func() {
+		c.fk20, c.fk20Err = crateKzg.NewFK20(c.domain, c.monomialG1())
+	})
+	return c.fk20, c.fk20Err
+}
+
+// getUpdateKeys returns the update keys used to keep cached proofs valid across updates.
+func (c *KZGContext) getUpdateKeys() *crateKzg.UpdateKeys {
+	c.updateKeysOnce.Do(func() {
+		c.updateKeys = crateKzg.NewUpdateKeys(c.domain, c.commitKey())
+	})
+	return c.updateKeys
+}
+
+// getPreparedKey returns the opening key of the setup, with the pairing lines of its G2 points precomputed.
+func (c *KZGContext) getPreparedKey() *crateKzg.PreparedOpeningKey {
+	c.preparedKeyOnce.Do(func() {
+		c.preparedKey = crateKzg.NewPreparedOpeningKey(&crateKzg.OpeningKey{
+			GenG1:   c.srs.Vk.G1,
+			GenG2:   c.srs.Vk.G2[0],
+			AlphaG2: c.srs.Vk.G2[1],
+		})
+	})
+	return c.preparedKey
+}
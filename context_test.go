@@ -0,0 +1,87 @@
+package fastcommit
+
+import (
+	"bytes"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+func TestKZGContext(t *testing.T) {
+	config, err := content.ReadFile("trusted_setup.json")
+	assert.Equal(t, nil, err)
+	setup, err := ParseSetupJSON(bytes.NewReader(config))
+	assert.Equal(t, nil, err)
+
+	ctx, err := NewKZGContext(setup)
+	assert.Equal(t, nil, err)
+	assert.NotSame(t, DefaultKZGContext(), ctx)
+	assert.Equal(t, DefaultKZGContext().SRSID(), ctx.SRSID())
+
+	// a branch committed with its own context matches the default one over the same setup
+	fc := ctx.NewValueCommit(dataCase)
+	assert.Same(t, ctx, fc.context())
+	assert.Equal(t, NewContext(dataCase).commit, fc.commit)
+
+	key := ctx.domain.Roots[2]
+	proof, err := fc.ProofForVal(key)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nil, fc.VerifyForVal(key, fc.values[2], proof))
+
+	// the zero value of a branch falls back to the default context
+	assert.Same(t, DefaultKZGContext(), (&ValueCommit{}).context())
+
+	small := &Setup{G1: setup.G1, LagrangeG1: setup.LagrangeG1[:16], G2: setup.G2}
+	_, err = NewKZGContext(small)
+	assert.Equal(t, ErrSetupSize, err)
+}
+
+func TestBitReversedOrder(t *testing.T) {
+	// go-kzg-4844 embeds the insecure setup of secret 1337
+	dev, err := NewInsecureDevSetup(ScalarSize, big.NewInt(1337))
+	assert.Equal(t, nil, err)
+	ctx, err := NewKZGContext(&dev.Setup, WithBitReversedOrder())
+	assert.Equal(t, nil, err)
+	gctx, err := gokzg4844.NewContext4096Insecure1337()
+	assert.Equal(t, nil, err)
+
+	fc := ctx.NewValueCommit(dataCase)
+	var blob gokzg4844.Blob
+	for i := range fc.values {
+		b := fc.values[i].Bytes()
+		copy(blob[i*fr.Bytes:], b[:])
+	}
+
+	// the branch has the commitment of its blob
+	commitment, err := gctx.BlobToKZGCommitment(blob, 0)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, [48]byte(commitment), fc.commit.Bytes())
+
+	// and the same opening proofs, slot i being at the bit-reversed root
+	key := ctx.domain.Roots[5]
+	proof, err := fc.ProofForVal(key)
+	assert.Equal(t, nil, err)
+	gproof, y, err := gctx.ComputeKZGProof(blob, key.Bytes(), 0)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, [48]byte(gproof), proof.Bytes())
+	assert.Equal(t, [32]byte(y), fc.values[5].Bytes())
+	assert.Equal(t, nil, fc.VerifyForVal(key, fc.values[5], proof))
+
+	// FK20 and aggregation follow the same order
+	proofs, err := fc.AllProofs()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, proof, proofs[5])
+	agg, err := ctx.AggregateProofs([]int{5, 9}, []bls12381.G1Affine{proofs[5], proofs[9]})
+	assert.Equal(t, nil, err)
+	keys := []fr.Element{ctx.domain.Roots[5], ctx.domain.Roots[9]}
+	assert.Equal(t, nil, fc.VerifyForVals(keys, []fr.Element{fc.values[5], fc.values[9]}, agg))
+
+	// the natural order gives different commitments over the same setup
+	natural, err := NewKZGContext(&dev.Setup)
+	assert.Equal(t, nil, err)
+	assert.NotEqual(t, fc.commit, natural.NewValueCommit(dataCase).commit)
+	assert.NotEqual(t, natural.SRSID(), ctx.SRSID())
+}
@@ -0,0 +1,173 @@
+package kzg
+
+import (
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github/yyjia/fastcommit/crateKzg/poly"
+)
+
+// AggregatedProof is a proof to the claim that a polynomial f(X) evaluates at the points
+// w_i of the domain, for i in Indices, to the ClaimedValues.
+//
+// Let A(X) be the polynomial vanishing on those points and R(X) the polynomial of degree
+// < len(Indices) interpolating the claimed values on them. QuotientCommitment is a commitment
+// to (f(X) - R(X)) / A(X).
+type AggregatedProof struct {
+	QuotientCommitment bls12381.G1Affine
+	Indices            []uint64
+	ClaimedValues      []fr.Element
+}
+
+// AggregateProofs combines opening proofs at points of the domain into the QuotientCommitment
+// of an [AggregatedProof], following aSVC. See [asvc].
+//
+// proofs[i] is the opening proof at domain.Roots[indices[i]]. The proofs may have been computed
+// independently; the evaluations are not needed to aggregate them, since
+//
+//	(f(X) - R(X)) / A(X) = Σ_i c_i (f(X) - f(w_i)) / (X - w_i),  with c_i = 1 / A'(w_i)
+//
+// [asvc]: https://eprint.iacr.org/2020/527
+func (domain *Domain) AggregateProofs(indices []uint64, proofs []bls12381.G1Affine) (bls12381.G1Affine, error) {
+	if len(indices) != len(proofs) {
+		return bls12381.G1Affine{}, ErrInvalidNumDigests
+	}
+	if len(indices) == 0 {
+		return bls12381.G1Affine{}, ErrInvalidIndices
+	}
+
+	c, _, err := domain.inverseVanishingDerivatives(indices)
+	if err != nil {
+		return bls12381.G1Affine{}, err
+	}
+
+	var res bls12381.G1Affine
+	_, err = res.MultiExp(proofs, c, ecc.MultiExpConfig{})
+	return res, err
+}
+
+// inverseVanishingDerivatives returns 1 / A'(w_i) for every index i, along with A(X) in monomial form,
+// where A(X) vanishes on the points of the domain at indices.
+func (domain *Domain) inverseVanishingDerivatives(indices []uint64) ([]fr.Element, []fr.Element, error) {
+	seen := make(map[uint64]struct{}, len(indices))
+	points := make([]fr.Element, len(indices))
+	for i, idx := range indices {
+		if idx >= domain.Cardinality {
+			return nil, nil, ErrInvalidIndices
+		}
+		if _, ok := seen[idx]; ok {
+			return nil, nil, ErrInvalidIndices
+		}
+		seen[idx] = struct{}{}
+		points[i] = domain.Roots[idx]
+	}
+	return poly.BarycentricWeights(points)
+}
+
+// VerifyAggregated verifies an [AggregatedProof] against the commitment to f.
+//
+// It checks e(C - [R(α)]G₁, G₂) == e(π, [A(α)]G₂), where [R(α)]G₁ is computed with the lagrange
+// commit key and [A(α)]G₂ with g2Powers, the G2 elements of the trusted setup in monomial form.
+// At least len(proof.Indices)+1 of them are needed.
+func VerifyAggregated(commitment *Commitment, proof *AggregatedProof, domain *Domain, ck *CommitKey, g2Powers []bls12381.G2Affine) error {
+	k := len(proof.Indices)
+	if k != len(proof.ClaimedValues) {
+		return ErrInvalidNumDigests
+	}
+	if k == 0 {
+		return ErrInvalidIndices
+	}
+	if len(g2Powers) < k+1 {
+		return ErrInsufficientG2Powers
+	}
+
+	c, vanishing, err := domain.inverseVanishingDerivatives(proof.Indices)
+	if err != nil {
+		return err
+	}
+
+	// [A(α)]G₂, with A(X) = Π (X - w_i) in monomial form
+	var vanishingG2 bls12381.G2Affine
+	if _, err := vanishingG2.MultiExp(g2Powers[:k+1], vanishing, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+
+	// [R(α)]G₁, with R(X) in lagrange form over the whole domain:
+	// R(w_m) = A(w_m) Σ_i y_i c_i / (w_m - w_i) for w_m not among the opened points.
+	interpolation := domain.interpolateOnDomain(proof.Indices, proof.ClaimedValues, c, vanishing)
+	interpolationCommit, err := Commit(interpolation, ck, 0)
+	if err != nil {
+		return err
+	}
+
+	var lhs bls12381.G1Affine
+	lhs.Sub(commitment, interpolationCommit)
+
+	var negG2 bls12381.G2Affine
+	negG2.Neg(&g2Powers[0])
+
+	check, err := bls12381.PairingCheck(
+		[]bls12381.G1Affine{lhs, proof.QuotientCommitment},
+		[]bls12381.G2Affine{negG2, vanishingG2},
+	)
+	if err != nil {
+		return err
+	}
+	if !check {
+		return ErrVerifyOpeningProof
+	}
+
+	return nil
+}
+
+// interpolateOnDomain returns, in lagrange form, the polynomial of degree < len(indices) that
+// takes values[i] at domain.Roots[indices[i]]. vanishing is the polynomial vanishing on those points,
+// in monomial form, and c are the inverses of its derivative at them.
+func (domain *Domain) interpolateOnDomain(indices []uint64, values, c, vanishing []fr.Element) Polynomial {
+	n := domain.Cardinality
+	res := make(Polynomial, n)
+	opened := make(map[uint64]int, len(indices))
+	for i, idx := range indices {
+		opened[idx] = i
+	}
+
+	// y_i c_i, shared by every point of the domain
+	weights := make([]fr.Element, len(indices))
+	for i := range weights {
+		weights[i].Mul(&values[i], &c[i])
+	}
+
+	// A(w_m), and the inverses 1 / (w_m - w_i), for every point that is not opened
+	vanishingEvals := make([]fr.Element, n)
+	denominators := make([]fr.Element, 0, uint64(len(indices))*n)
+	for m := uint64(0); m < n; m++ {
+		if _, ok := opened[m]; ok {
+			continue
+		}
+		vanishingEvals[m] = poly.Evaluate(vanishing, domain.Roots[m])
+		for _, idx := range indices {
+			var tmp fr.Element
+			tmp.Sub(&domain.Roots[m], &domain.Roots[idx])
+			denominators = append(denominators, tmp)
+		}
+	}
+	denominators = fr.BatchInvert(denominators)
+
+	pos := 0
+	for m := uint64(0); m < n; m++ {
+		if i, ok := opened[m]; ok {
+			res[m] = values[i]
+			continue
+		}
+		var sum fr.Element
+		for i := range indices {
+			var tmp fr.Element
+			tmp.Mul(&weights[i], &denominators[pos+i])
+			sum.Add(&sum, &tmp)
+		}
+		res[m].Mul(&sum, &vanishingEvals[m])
+		pos += len(indices)
+	}
+
+	return res
+}
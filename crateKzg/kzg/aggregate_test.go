@@ -0,0 +1,73 @@
+package kzg
+
+import (
+	"math/big"
+	"testing"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+func TestAggregateProofs(t *testing.T) {
+	domain := NewDomain(16)
+	secret := big.NewInt(1234)
+	srs, _ := newLagrangeSRSInsecure(*domain, secret)
+	g2Powers := insecureG2Powers(secret, 5)
+
+	poly := randPoly(t, *domain)
+	comm, _ := Commit(poly, &srs.CommitKey, 0)
+
+	indices := []uint64{1, 4, 9, 15}
+	proofs := make([]bls12381.G1Affine, len(indices))
+	values := make([]fr.Element, len(indices))
+	for i, idx := range indices {
+		proof, err := Open(domain, poly, domain.Roots[idx], &srs.CommitKey, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		proofs[i] = proof.QuotientCommitment
+		values[i] = poly[idx]
+	}
+
+	aggregated, err := domain.AggregateProofs(indices, proofs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof := AggregatedProof{aggregated, indices, values}
+	if err := VerifyAggregated(comm, &proof, domain, &srs.CommitKey, g2Powers); err != nil {
+		t.Fatalf("aggregated proof failed to verify: %v", err)
+	}
+
+	// A wrong value must be rejected
+	proof.ClaimedValues[2].Add(&proof.ClaimedValues[2], new(fr.Element).SetOne())
+	if err := VerifyAggregated(comm, &proof, domain, &srs.CommitKey, g2Powers); err == nil {
+		t.Fatal("aggregated proof with a wrong value verified")
+	}
+
+	// Repeated indices can not be aggregated
+	if _, err := domain.AggregateProofs([]uint64{1, 1}, proofs[:2]); err != ErrInvalidIndices {
+		t.Fatalf("expected ErrInvalidIndices, got %v", err)
+	}
+
+	// Not enough G2 powers for the number of openings
+	proof.ClaimedValues[2].Sub(&proof.ClaimedValues[2], new(fr.Element).SetOne())
+	if err := VerifyAggregated(comm, &proof, domain, &srs.CommitKey, g2Powers[:4]); err != ErrInsufficientG2Powers {
+		t.Fatalf("expected ErrInsufficientG2Powers, got %v", err)
+	}
+}
+
+func insecureG2Powers(secret *big.Int, n int) []bls12381.G2Affine {
+	_, _, _, gen2 := bls12381.Generators()
+	var alpha, power fr.Element
+	alpha.SetBigInt(secret)
+	power.SetOne()
+
+	res := make([]bls12381.G2Affine, n)
+	for i := range res {
+		var bi big.Int
+		power.BigInt(&bi)
+		res[i].ScalarMultiplication(&gen2, &bi)
+		power.Mul(&power, &alpha)
+	}
+	return res
+}
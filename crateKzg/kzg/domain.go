@@ -39,6 +39,9 @@ type Domain struct {
 	// f(x)/g(x) where g(x) is a linear polynomial
 	// which vanishes on a point on the domain
 	PreComputedInverses []fr.Element
+
+	// bitReversed is set while Roots are in bit-reversed order
+	bitReversed bool
 }
 
 // NewDomain returns a new domain with the desired number of points x.
@@ -150,6 +153,12 @@ func bitReverse[K interface{}](list []K) {
 func (domain *Domain) ReverseRoots() {
 	bitReverse(domain.Roots)
 	bitReverse(domain.PreComputedInverses)
+	domain.bitReversed = !domain.bitReversed
+}
+
+// BitReversed reports whether the roots are in bit-reversed order, as after an odd number of calls to ReverseRoots.
+func (domain *Domain) BitReversed() bool {
+	return domain.bitReversed
 }
 
 // findRootIndex returns the index of the element in the domain or -1 if not found.
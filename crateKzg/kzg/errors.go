@@ -8,4 +8,9 @@ var (
 	ErrVerifyOpeningProof             = errors.New("can't verify opening proof")
 	ErrPolynomialMismatchedSizeDomain = errors.New("domain size does not equal the number of evaluations in the polynomial")
 	ErrMinSRSSize                     = errors.New("minimum srs size is 2")
+	ErrSRSSizeMismatch                = errors.New("srs size does not match the domain size")
+	ErrInvalidIndices                 = errors.New("indices are empty, repeated or outside the domain")
+	ErrInsufficientG2Powers           = errors.New("not enough G2 powers in the srs for the number of openings")
+	ErrZeroSecret                     = errors.New("the secret of an insecure srs must be non-zero modulo the group order")
+	ErrZeroCosetOffset                = errors.New("the offset of a coset must be non-zero")
 )
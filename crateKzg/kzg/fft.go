@@ -42,6 +42,81 @@ func (domain *Domain) IfftG1(values []bls12381.G1Affine) []bls12381.G1Affine {
 	return inverseFFT
 }
 
+// FftFr computes an FFT (Fast Fourier Transform) of the field elements, that is the evaluations
+// on the domain of the polynomial with coefficients values.
+//
+// len(values) must be the cardinality of the domain. The elements are returned in a new slice, in order
+// as opposed to being returned in bit-reversed order.
+func (domain *Domain) FftFr(values []fr.Element) ([]fr.Element, error) {
+	if uint64(len(values)) != domain.Cardinality {
+		return nil, ErrPolynomialMismatchedSizeDomain
+	}
+	return fftFr(values, domain.Generator), nil
+}
+
+// IfftFr computes an IFFT (Inverse Fast Fourier Transform) of the field elements, that is the
+// coefficients of the polynomial taking the values on the domain.
+//
+// len(values) must be the cardinality of the domain. The elements are returned in a new slice, in order
+// as opposed to being returned in bit-reversed order.
+func (domain *Domain) IfftFr(values []fr.Element) ([]fr.Element, error) {
+	if uint64(len(values)) != domain.Cardinality {
+		return nil, ErrPolynomialMismatchedSizeDomain
+	}
+	inverseFFT := fftFr(values, domain.GeneratorInv)
+
+	// scale by the inverse of the domain size
+	for i := 0; i < len(inverseFFT); i++ {
+		inverseFFT[i].Mul(&inverseFFT[i], &domain.CardinalityInv)
+	}
+
+	return inverseFFT, nil
+}
+
+// CosetFftFr computes the evaluations on the coset offset * domain of the polynomial with coefficients values.
+//
+// The offset must not be in the domain, e.g. a generator of the multiplicative group of the field.
+// len(values) must be the cardinality of the domain.
+func (domain *Domain) CosetFftFr(values []fr.Element, offset fr.Element) ([]fr.Element, error) {
+	if uint64(len(values)) != domain.Cardinality {
+		return nil, ErrPolynomialMismatchedSizeDomain
+	}
+	if offset.IsZero() {
+		return nil, ErrZeroCosetOffset
+	}
+	// f(offset X) has coefficients f_i offset^i
+	scaled := make([]fr.Element, len(values))
+	power := fr.One()
+	for i := range values {
+		scaled[i].Mul(&values[i], &power)
+		power.Mul(&power, &offset)
+	}
+	return domain.FftFr(scaled)
+}
+
+// CosetIfftFr computes the coefficients of the polynomial taking the values on the coset offset * domain.
+//
+// This is the inverse of [Domain.CosetFftFr] with the same offset.
+func (domain *Domain) CosetIfftFr(values []fr.Element, offset fr.Element) ([]fr.Element, error) {
+	// the inverse of zero is zero in gnark-crypto, which would silently return zeroes
+	if offset.IsZero() {
+		return nil, ErrZeroCosetOffset
+	}
+	coeffs, err := domain.IfftFr(values)
+	if err != nil {
+		return nil, err
+	}
+
+	var offsetInv fr.Element
+	offsetInv.Inverse(&offset)
+	power := fr.One()
+	for i := range coeffs {
+		coeffs[i].Mul(&coeffs[i], &power)
+		power.Mul(&power, &offsetInv)
+	}
+	return coeffs, nil
+}
+
 // fftG1 computes an FFT (Fast Fourier Transform) of the G1 elements.
 //
 // This is the actual implementation of [FftG1] with the same convention.
@@ -92,6 +167,80 @@ func fftG1(values []bls12381.G1Affine, nthRootOfUnity fr.Element) []bls12381.G1A
 	return evaluations
 }
 
+// fftG1Jac computes an FFT (Fast Fourier Transform) of G1 elements in Jacobian coordinates.
+//
+// This follows [fftG1] with the same conventions. Staying in Jacobian coordinates avoids
+// a field inversion per group operation, which matters on the hot path of [FK20].
+func fftG1Jac(values []bls12381.G1Jac, nthRootOfUnity fr.Element) []bls12381.G1Jac {
+	n := len(values)
+	if n == 1 {
+		return values
+	}
+
+	var generatorSquared fr.Element
+	generatorSquared.Square(&nthRootOfUnity) // generator with order n/2
+
+	even, odd := takeEvenOdd(values)
+
+	fftEven := fftG1Jac(even, generatorSquared)
+	fftOdd := fftG1Jac(odd, generatorSquared)
+
+	inputPoint := fr.One()
+	evaluations := make([]bls12381.G1Jac, n)
+	for k := 0; k < n/2; k++ {
+		var tmp bls12381.G1Jac
+		if inputPoint.IsOne() {
+			tmp.Set(&fftOdd[k])
+		} else {
+			var inputPointBI big.Int
+			inputPoint.BigInt(&inputPointBI)
+			tmp.ScalarMultiplication(&fftOdd[k], &inputPointBI)
+		}
+
+		evaluations[k].Set(&fftEven[k])
+		evaluations[k].AddAssign(&tmp)
+		evaluations[k+n/2].Set(&fftEven[k])
+		evaluations[k+n/2].SubAssign(&tmp)
+
+		inputPoint.Mul(&inputPoint, &nthRootOfUnity)
+	}
+
+	return evaluations
+}
+
+// fftFr computes an FFT (Fast Fourier Transform) of the field elements.
+//
+// This follows [fftG1] with the same conventions, over scalars instead of G1 elements.
+// The result never aliases values.
+func fftFr(values []fr.Element, nthRootOfUnity fr.Element) []fr.Element {
+	n := len(values)
+	if n <= 1 {
+		return append([]fr.Element(nil), values...)
+	}
+
+	var generatorSquared fr.Element
+	generatorSquared.Square(&nthRootOfUnity) // generator with order n/2
+
+	even, odd := takeEvenOdd(values)
+
+	fftEven := fftFr(even, generatorSquared)
+	fftOdd := fftFr(odd, generatorSquared)
+
+	inputPoint := fr.One()
+	evaluations := make([]fr.Element, n)
+	for k := 0; k < n/2; k++ {
+		var tmp fr.Element
+		tmp.Mul(&inputPoint, &fftOdd[k])
+
+		evaluations[k].Add(&fftEven[k], &tmp)
+		evaluations[k+n/2].Sub(&fftEven[k], &tmp)
+
+		inputPoint.Mul(&inputPoint, &nthRootOfUnity)
+	}
+
+	return evaluations
+}
+
 // takeEvenOdd Takes a slice and return two slices
 // The first slice contains (a copy of) all of the elements
 // at even indices, the second slice contains
@@ -3,6 +3,9 @@ package kzg
 import (
 	"math/big"
 	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSRSConversion(t *testing.T) {
@@ -26,3 +29,67 @@ func TestSRSConversion(t *testing.T) {
 		}
 	}
 }
+
+func TestFftFr(t *testing.T) {
+	domain := NewDomain(16)
+	coeffs := make([]fr.Element, domain.Cardinality)
+	for i := range coeffs {
+		coeffs[i].SetUint64(uint64(3*i + 1))
+	}
+
+	// The FFT evaluates the polynomial on the domain
+	evals, err := domain.FftFr(coeffs)
+	require.NoError(t, err)
+	for i := range evals {
+		want := evalMonomial(coeffs, domain.Roots[i])
+		require.True(t, evals[i].Equal(&want))
+	}
+	back, err := domain.IfftFr(evals)
+	require.NoError(t, err)
+	require.Equal(t, coeffs, back)
+
+	// and the coset FFT on offset * domain
+	offset := fr.NewElement(7)
+	cosetEvals, err := domain.CosetFftFr(coeffs, offset)
+	require.NoError(t, err)
+	for i := range cosetEvals {
+		var x fr.Element
+		x.Mul(&offset, &domain.Roots[i])
+		want := evalMonomial(coeffs, x)
+		require.True(t, cosetEvals[i].Equal(&want))
+	}
+	back, err = domain.CosetIfftFr(cosetEvals, offset)
+	require.NoError(t, err)
+	require.Equal(t, coeffs, back)
+
+	// the length must be the cardinality of the domain
+	_, err = domain.FftFr(coeffs[:8])
+	require.ErrorIs(t, err, ErrPolynomialMismatchedSizeDomain)
+	_, err = domain.IfftFr(nil)
+	require.ErrorIs(t, err, ErrPolynomialMismatchedSizeDomain)
+	_, err = domain.CosetFftFr(coeffs[:1], offset)
+	require.ErrorIs(t, err, ErrPolynomialMismatchedSizeDomain)
+
+	// and a coset has a non-zero offset
+	_, err = domain.CosetFftFr(coeffs, fr.Element{})
+	require.ErrorIs(t, err, ErrZeroCosetOffset)
+	_, err = domain.CosetIfftFr(cosetEvals, fr.Element{})
+	require.ErrorIs(t, err, ErrZeroCosetOffset)
+
+	// a domain of one point returns a copy, which the inverse scales without touching the input
+	one := NewDomain(1)
+	value := []fr.Element{fr.NewElement(5)}
+	res, err := one.IfftFr(value)
+	require.NoError(t, err)
+	res[0].SetOne()
+	require.Equal(t, fr.NewElement(5), value[0])
+}
+
+func evalMonomial(coeffs []fr.Element, x fr.Element) fr.Element {
+	var res fr.Element
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		res.Mul(&res, &x)
+		res.Add(&res, &coeffs[i])
+	}
+	return res
+}
@@ -0,0 +1,103 @@
+package kzg
+
+import (
+	"math/big"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// FK20 computes the opening proofs of a polynomial at every point of the domain at once,
+// following the Feist–Khovratovich method. See [fk20].
+//
+// For a polynomial f(X) = f_0 + f_1 X + ... + f_{n-1} X^{n-1}, the proof at w^k is [q_k(α)]G₁
+// with q_k(X) = (f(X) - f(w^k)) / (X - w^k). Writing
+//
+//	h_i = f_{i+1} [α^0]G₁ + f_{i+2} [α^1]G₁ + ... + f_{n-1} [α^{n-2-i}]G₁
+//
+// one finds that the proofs are the evaluations of h(X) = Σ h_i X^i at the points of the domain.
+// The vector h is a Toeplitz matrix-vector product, which is embedded into a circulant
+// matrix of size 2n and computed with FFTs. Overall this costs O(n log n) group operations
+// instead of the O(n^2) of calling [Open] on every point.
+//
+// [fk20]: https://eprint.iacr.org/2023/033
+type FK20 struct {
+	domain *Domain
+	// extDomain has twice the size of domain, it holds the circulant embedding.
+	extDomain *Domain
+	// srsFFT is the FFT over extDomain of [α^{n-2}]G₁, ..., [α^0]G₁ followed by n+1 zeroes.
+	// It only depends on the setup, so it is computed once.
+	srsFFT []bls12381.G1Affine
+}
+
+// NewFK20 precomputes the data needed to compute all proofs over domain.
+//
+// monomialG1 are the G1 elements of the trusted setup in monomial form, [α^i]G₁;
+// at least domain.Cardinality-1 of them are needed.
+func NewFK20(domain *Domain, monomialG1 []bls12381.G1Affine) (*FK20, error) {
+	n := domain.Cardinality
+	if n < 2 || uint64(len(monomialG1)) < n-1 {
+		return nil, ErrSRSSizeMismatch
+	}
+
+	x := make([]bls12381.G1Jac, 2*n)
+	for i := uint64(0); i < n-1; i++ {
+		x[i].FromAffine(&monomialG1[n-2-i])
+	}
+
+	extDomain := NewDomain(2 * n)
+	return &FK20{
+		domain:    domain,
+		extDomain: extDomain,
+		srsFFT:    bls12381.BatchJacobianToAffineG1(fftG1Jac(x, extDomain.Generator)),
+	}, nil
+}
+
+// ComputeAllProofs returns the opening proofs of p at every point of the domain.
+//
+// The polynomial is given in lagrange form, p[k] being its value at domain.Roots[k], and
+// proofs[k] opens p at domain.Roots[k], whether the roots are bit-reversed or not.
+func (fk *FK20) ComputeAllProofs(p Polynomial) ([]bls12381.G1Affine, error) {
+	n := fk.domain.Cardinality
+	if uint64(len(p)) != n {
+		return nil, ErrPolynomialMismatchedSizeDomain
+	}
+
+	// The FFTs work on the powers of domain.Generator, in natural order
+	if fk.domain.bitReversed {
+		natural := make(Polynomial, n)
+		copy(natural, p)
+		bitReverse(natural)
+		p = natural
+	}
+
+	// Monomial coefficients of p
+	coeffs, err := fk.domain.IfftFr(p)
+	if err != nil {
+		return nil, err
+	}
+
+	// First column of the circulant matrix embedding the Toeplitz matrix:
+	// [f_{n-1}, 0, ..., 0, f_0, f_1, ..., f_{n-2}] with n zeroes.
+	c := make([]fr.Element, 2*n)
+	c[0] = coeffs[n-1]
+	copy(c[n+1:], coeffs[:n-1])
+	cFFT := fftFr(c, fk.extDomain.Generator)
+
+	// The circulant matrix-vector product is a pointwise product in evaluation form.
+	// The scaling of the inverse FFT below is applied here, on scalars, rather than on points.
+	y := make([]bls12381.G1Jac, 2*n)
+	for i := range y {
+		var bi big.Int
+		cFFT[i].Mul(&cFFT[i], &fk.extDomain.CardinalityInv)
+		cFFT[i].BigInt(&bi)
+		y[i].ScalarMultiplicationAffine(&fk.srsFFT[i], &bi)
+	}
+
+	h := fftG1Jac(y, fk.extDomain.GeneratorInv)[:n]
+	proofs := bls12381.BatchJacobianToAffineG1(fftG1Jac(h, fk.domain.Generator))
+	if fk.domain.bitReversed {
+		bitReverse(proofs)
+	}
+	return proofs, nil
+}
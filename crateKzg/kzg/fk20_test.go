@@ -0,0 +1,57 @@
+package kzg
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+func TestFK20ComputeAllProofs(t *testing.T) {
+	tests := []struct {
+		size        uint64
+		bitReversed bool
+	}{
+		{size: 4},
+		{size: 16},
+		{size: 16, bitReversed: true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("size=%d/bitReversed=%v", tt.size, tt.bitReversed), func(t *testing.T) {
+			domain := NewDomain(tt.size)
+			secret := big.NewInt(1234)
+			srsMonomial, err := newMonomialSRSInsecureUint64(domain.Cardinality, secret)
+			if err != nil {
+				t.Fatal(err)
+			}
+			srsLagrange, err := newLagrangeSRSInsecure(*domain, secret)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.bitReversed {
+				domain.ReverseRoots()
+				srsLagrange.CommitKey.ReversePoints()
+			}
+
+			fk, err := NewFK20(domain, srsMonomial.CommitKey.G1)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			poly := randPoly(t, *domain)
+			proofs, err := fk.ComputeAllProofs(poly)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i := 0; i < int(domain.Cardinality); i++ {
+				expected, err := Open(domain, poly, domain.Roots[i], &srsLagrange.CommitKey, 0)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !proofs[i].Equal(&expected.QuotientCommitment) {
+					t.Fatalf("proof %d differs from the one computed by Open", i)
+				}
+			}
+		})
+	}
+}
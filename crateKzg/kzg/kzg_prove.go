@@ -42,6 +42,12 @@ func Open(domain *Domain, p Polynomial, evaluationPoint fr.Element, ck *CommitKe
 	return res, nil
 }
 
+// ComputeQuotientPoly computes q(X) = (f(X) - f(z)) / (X - z) in Lagrange form, for z inside or
+// outside of the domain. fz must be the evaluation of f at z, it is not checked.
+func (domain *Domain) ComputeQuotientPoly(f Polynomial, z, fz fr.Element) (Polynomial, error) {
+	return domain.computeQuotientPoly(f, domain.findRootIndex(z), fz, z)
+}
+
 // computeQuotientPoly computes q(X) = (f(X) - f(z)) / (X - z) in Lagrange form.
 //
 // We refer to the result q(X) as the quotient polynomial.
@@ -1,6 +1,7 @@
 package kzg
 
 import (
+	"bytes"
 	"math/big"
 	"testing"
 
@@ -98,6 +99,19 @@ func TestComputeQuotientPolySmoke(t *testing.T) {
 }
 
 // This is the way it is done in the consensus-specs
+func TestComputeQuotientPolyExported(t *testing.T) {
+	domain := NewDomain(16)
+	poly := randPoly(t, *domain)
+
+	for _, z := range []fr.Element{domain.Roots[5], randomScalarNotInDomain(t, *domain)} {
+		fz, err := domain.EvaluateLagrangePolynomial(poly, z)
+		require.NoError(t, err)
+		got, err := domain.ComputeQuotientPoly(poly, z, *fz)
+		require.NoError(t, err)
+		require.Equal(t, computeQuotientPolySlow(*domain, poly, z), got)
+	}
+}
+
 func computeQuotientPolySlow(domain Domain, f Polynomial, z fr.Element) Polynomial {
 	quotient := make([]fr.Element, len(f))
 	y, err := domain.EvaluateLagrangePolynomial(f, z)
@@ -184,3 +198,37 @@ func randomScalarNotInDomain(t *testing.T, domain Domain) fr.Element {
 	}
 	return randFr
 }
+
+func TestBatchVerifyInjectedRandomness(t *testing.T) {
+	domain := NewDomain(4)
+	srs, _ := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+
+	proof0, commitment0 := randValidOpeningProof(t, *domain, *srs)
+	proof1, commitment1 := randValidOpeningProof(t, *domain, *srs)
+	commitments := []Commitment{commitment0, commitment1}
+	proofs := []OpeningProof{proof0, proof1}
+
+	require.NoError(t, BatchVerifyMultiPointsDeterministic(commitments, proofs, &srs.OpeningKey))
+
+	// With a known batching scalar r = 7, shifting the claimed values by δ and -δ/r
+	// cancels out in the folded evaluation, so two invalid proofs pass together.
+	scalar := make([]byte, 64)
+	scalar[63] = 7
+	r := fr.NewElement(7)
+	delta := fr.NewElement(3)
+	var shift fr.Element
+	shift.Div(&delta, &r)
+	proofs[0].ClaimedValue.Add(&proofs[0].ClaimedValue, &delta)
+	proofs[1].ClaimedValue.Sub(&proofs[1].ClaimedValue, &shift)
+
+	err := BatchVerifyMultiPointsWithReader(commitments, proofs, &srs.OpeningKey, bytes.NewReader(scalar))
+	require.NoError(t, err)
+
+	// A batching scalar that the prover cannot predict catches them.
+	require.Error(t, BatchVerifyMultiPointsDeterministic(commitments, proofs, &srs.OpeningKey))
+	require.Error(t, BatchVerifyMultiPoints(commitments, proofs, &srs.OpeningKey))
+
+	// An exhausted reader is reported.
+	err = BatchVerifyMultiPointsWithReader(commitments, proofs, &srs.OpeningKey, bytes.NewReader(nil))
+	require.Error(t, err)
+}
@@ -1,6 +1,8 @@
 package kzg
 
 import (
+	"crypto/rand"
+	"io"
 	"math/big"
 
 	"github.com/consensys/gnark-crypto/ecc"
@@ -97,16 +99,80 @@ func Verify(commitment *Commitment, proof *OpeningProof, openKey *OpeningKey) er
 	return nil
 }
 
+// DomSepBatchVerify is a Domain Separator for the transcript of [BatchVerifyMultiPointsDeterministic].
+const DomSepBatchVerify = "KZG_BATCH_VERIFY_V1_"
+
 // BatchVerifyMultiPoints verifies multiple KZG proofs in a batch. See [verify_kzg_proof_batch].
 //
 //   - This method is more efficient than calling [Verify] multiple times.
-//   - Randomness is used to combine multiple proofs into one.
+//   - Randomness is used to combine multiple proofs into one. It is read from the OS,
+//     see [BatchVerifyMultiPointsWithReader] and [BatchVerifyMultiPointsDeterministic] otherwise.
 //
 // Modified from [gnark-crypto].
 //
 // [verify_kzg_proof_batch]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#verify_kzg_proof_batch
 // [gnark-crypto]: https://github.com/ConsenSys/gnark-crypto/blob/8f7ca09273c24ed9465043566906cbecf5dcee91/ecc/bls12-381/fr/kzg/kzg.go#L367)
 func BatchVerifyMultiPoints(commitments []Commitment, proofs []OpeningProof, openKey *OpeningKey) error {
+	return BatchVerifyMultiPointsWithReader(commitments, proofs, openKey, rand.Reader)
+}
+
+// BatchVerifyMultiPointsWithReader is [BatchVerifyMultiPoints] with the randomness read from reader.
+//
+// The batch is only sound if the verifier's randomness is unpredictable to the prover.
+// A fixed reader is meant for tests.
+func BatchVerifyMultiPointsWithReader(commitments []Commitment, proofs []OpeningProof, openKey *OpeningKey, reader io.Reader) error {
+	return batchVerifyMultiPoints(commitments, proofs, openKey, func() (fr.Element, error) {
+		return randomScalar(reader)
+	})
+}
+
+// BatchVerifyMultiPointsDeterministic is [BatchVerifyMultiPoints] with the batching scalar derived
+// from a transcript over the opening key and every commitment and proof.
+//
+// This is sound as well: the prover cannot choose the proofs after the scalar, since any
+// change to them changes the scalar.
+func BatchVerifyMultiPointsDeterministic(commitments []Commitment, proofs []OpeningProof, openKey *OpeningKey) error {
+	return batchVerifyMultiPoints(commitments, proofs, openKey, transcriptScalar(commitments, proofs, openKey))
+}
+
+// transcriptScalar returns the sampler of [BatchVerifyMultiPointsDeterministic]: a challenge of a
+// transcript over the opening key and every commitment and proof.
+func transcriptScalar(commitments []Commitment, proofs []OpeningProof, openKey *OpeningKey) func() (fr.Element, error) {
+	return func() (fr.Element, error) {
+		transcript := NewTranscript(DomSepBatchVerify)
+		genG2 := openKey.GenG2.Bytes()
+		alphaG2 := openKey.AlphaG2.Bytes()
+		transcript.AppendPoint("G1", &openKey.GenG1)
+		transcript.AppendBytes("G2", genG2[:])
+		transcript.AppendBytes("alphaG2", alphaG2[:])
+		transcript.AppendUint64("proofs", uint64(len(proofs)))
+		for i := range proofs {
+			transcript.AppendPoint("C", &commitments[i])
+			transcript.AppendScalar("z", &proofs[i].InputPoint)
+			transcript.AppendScalar("y", &proofs[i].ClaimedValue)
+			transcript.AppendPoint("pi", &proofs[i].QuotientCommitment)
+		}
+		return transcript.ChallengeScalar("r"), nil
+	}
+}
+
+// randomScalar reads a uniformly random non-zero scalar from reader.
+func randomScalar(reader io.Reader) (fr.Element, error) {
+	// 64 bytes reduced modulo r are statistically close to uniform
+	var buf [64]byte
+	var res fr.Element
+	for res.IsZero() {
+		if _, err := io.ReadFull(reader, buf[:]); err != nil {
+			return fr.Element{}, err
+		}
+		res.SetBytes(buf[:])
+	}
+	return res, nil
+}
+
+// batchVerifyMultiPoints implements the batch verification, sample is only called when
+// there are at least two proofs to combine.
+func batchVerifyMultiPoints(commitments []Commitment, proofs []OpeningProof, openKey *OpeningKey, sample func() (fr.Element, error)) error {
 	// Check consistency number of proofs is equal to the number of commitments.
 	if len(commitments) != len(proofs) {
 		return ErrInvalidNumDigests
@@ -125,30 +191,55 @@ func BatchVerifyMultiPoints(commitments []Commitment, proofs []OpeningProof, ope
 		return Verify(&commitments[0], &proofs[0], openKey)
 	}
 
+	lhs, negQuotients, err := foldProofs(commitments, proofs, &openKey.GenG1, sample)
+	if err != nil {
+		return err
+	}
+
+	check, err := bls12381.PairingCheck(
+		[]bls12381.G1Affine{lhs, negQuotients},
+		[]bls12381.G2Affine{openKey.GenG2, openKey.AlphaG2},
+	)
+	if err != nil {
+		return err
+	}
+	if !check {
+		return ErrVerifyOpeningProof
+	}
+
+	return nil
+}
+
+// foldProofs combines the proofs with the powers of a scalar r drawn by sample. The batch is valid
+// if and only if e(lhs, G₂) e(negQuotients, [α]G₂) == 1, where
+//
+//	lhs          = Σ rⁱ ([f_i(α) - f_i(z_i)]G₁ + [z_i]π_i)
+//	negQuotients = -Σ rⁱ π_i
+func foldProofs(commitments []Commitment, proofs []OpeningProof, genG1 *bls12381.G1Affine, sample func() (fr.Element, error)) (lhs, negQuotients bls12381.G1Affine, err error) {
+	batchSize := len(commitments)
+
 	// Sample random numbers for sampling.
 	//
 	// We only need to sample one random number and
 	// compute powers of that random number. This works
 	// since powers will produce a vandermonde matrix
 	// which is linearly independent.
-	var randomNumber fr.Element
-	_, err := randomNumber.SetRandom()
+	randomNumber, err := sample()
 	if err != nil {
-		return err
+		return lhs, negQuotients, err
 	}
 	randomNumbers := utils.ComputePowers(randomNumber, uint(batchSize))
 
 	// Combine random_i*quotient_i
-	var foldedQuotients bls12381.G1Affine
 	quotients := make([]bls12381.G1Affine, len(proofs))
 	for i := 0; i < batchSize; i++ {
 		quotients[i].Set(&proofs[i].QuotientCommitment)
 	}
 	config := ecc.MultiExpConfig{}
-	_, err = foldedQuotients.MultiExp(quotients, randomNumbers, config)
-	if err != nil {
-		return err
+	if _, err = negQuotients.MultiExp(quotients, randomNumbers, config); err != nil {
+		return lhs, negQuotients, err
 	}
+	negQuotients.Neg(&negQuotients)
 
 	// Fold commitments and evaluations using randomness
 	evaluations := make([]fr.Element, batchSize)
@@ -157,14 +248,14 @@ func BatchVerifyMultiPoints(commitments []Commitment, proofs []OpeningProof, ope
 	}
 	foldedCommitments, foldedEvaluations, err := fold(commitments, evaluations, randomNumbers)
 	if err != nil {
-		return err
+		return lhs, negQuotients, err
 	}
 
 	// Compute commitment to folded Eval
 	var foldedEvaluationsCommit bls12381.G1Affine
 	var foldedEvaluationsBigInt big.Int
 	foldedEvaluations.BigInt(&foldedEvaluationsBigInt)
-	foldedEvaluationsCommit.ScalarMultiplication(&openKey.GenG1, &foldedEvaluationsBigInt)
+	foldedEvaluationsCommit.ScalarMultiplication(genG1, &foldedEvaluationsBigInt)
 
 	// Compute F = foldedCommitments - foldedEvaluationsCommit
 	foldedCommitments.Sub(&foldedCommitments, &foldedEvaluationsCommit)
@@ -174,29 +265,12 @@ func BatchVerifyMultiPoints(commitments []Commitment, proofs []OpeningProof, ope
 	for i := 0; i < batchSize; i++ {
 		randomNumbers[i].Mul(&randomNumbers[i], &proofs[i].InputPoint)
 	}
-	_, err = foldedPointsQuotients.MultiExp(quotients, randomNumbers, config)
-	if err != nil {
-		return err
+	if _, err = foldedPointsQuotients.MultiExp(quotients, randomNumbers, config); err != nil {
+		return lhs, negQuotients, err
 	}
 
-	// `lhs` first pairing
-	foldedCommitments.Add(&foldedCommitments, &foldedPointsQuotients)
-
-	// `lhs` second pairing
-	foldedQuotients.Neg(&foldedQuotients)
-
-	check, err := bls12381.PairingCheck(
-		[]bls12381.G1Affine{foldedCommitments, foldedQuotients},
-		[]bls12381.G2Affine{openKey.GenG2, openKey.AlphaG2},
-	)
-	if err != nil {
-		return err
-	}
-	if !check {
-		return ErrVerifyOpeningProof
-	}
-
-	return nil
+	lhs.Add(&foldedCommitments, &foldedPointsQuotients)
+	return lhs, negQuotients, nil
 }
 
 // fold computes two inner products with the same factors:
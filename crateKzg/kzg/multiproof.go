@@ -0,0 +1,213 @@
+package kzg
+
+import (
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// DomSepMultiOpen is a Domain Separator for the transcript of [MultiOpen].
+const DomSepMultiOpen = "KZG_MULTIOPEN_V1_"
+
+// MultiOpeningProof is a proof to the claim that polynomials f_i(X) (represented by commitments C_i)
+// evaluate at points z_i to y_i. Several openings may concern the same polynomial.
+//
+// It follows the multiproof of Dankrad Feist, see [multiproof]. With r_i the powers of a challenge r:
+//
+//	g(X) = Σ r_i (f_i(X) - y_i) / (X - z_i)                 D = [g(α)]G₁
+//	h(X) = Σ r_i f_i(X) / (t - z_i)                         E = Σ r_i / (t - z_i) C_i
+//
+// where t is a second challenge, drawn after D. Then (h - g)(t) = Σ r_i y_i / (t - z_i), which the
+// verifier computes by itself, and QuotientCommitment opens E - D at t.
+//
+// [multiproof]: https://dankradfeist.de/ethereum/2021/06/18/pcs-multiproofs.html
+type MultiOpeningProof struct {
+	// Commitment to g(X) : `D`
+	D bls12381.G1Affine
+
+	// Commitment to ((h - g)(X) - (h - g)(t)) / (X - t)
+	QuotientCommitment bls12381.G1Affine
+
+	// Points that we are evaluating the polynomials at : `z_i`
+	InputPoints []fr.Element
+
+	// ClaimedValues purported values : `f_i(z_i)`
+	ClaimedValues []fr.Element
+}
+
+// MultiOpen proves that polys[i] evaluates at points[i] to its claimed value, for all i, with a
+// single [MultiOpeningProof]. The points may be inside or outside of the domain.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number of CPUs.
+func MultiOpen(domain *Domain, polys []Polynomial, commitments []Commitment, points []fr.Element, ck *CommitKey, numGoRoutines int) (MultiOpeningProof, error) {
+	return MultiOpenWithTranscript(NewTranscript(DomSepMultiOpen), domain, polys, commitments, points, ck, numGoRoutines)
+}
+
+// MultiOpenWithTranscript is [MultiOpen] with the challenges drawn from transcript, once it has absorbed
+// the openings. A caller proving a larger structure binds its own context into transcript first, and
+// verifies with a transcript built the same way.
+func MultiOpenWithTranscript(transcript *Transcript, domain *Domain, polys []Polynomial, commitments []Commitment, points []fr.Element, ck *CommitKey, numGoRoutines int) (MultiOpeningProof, error) {
+	if len(polys) != len(commitments) || len(polys) != len(points) {
+		return MultiOpeningProof{}, ErrInvalidNumDigests
+	}
+	if len(polys) == 0 {
+		return MultiOpeningProof{}, ErrInvalidIndices
+	}
+
+	values := make([]fr.Element, len(polys))
+	quotients := make([]Polynomial, len(polys))
+	for i := range polys {
+		value, indexInDomain, err := domain.evaluateLagrangePolynomial(polys[i], points[i])
+		if err != nil {
+			return MultiOpeningProof{}, err
+		}
+		values[i] = *value
+
+		quotients[i], err = domain.computeQuotientPoly(polys[i], indexInDomain, values[i], points[i])
+		if err != nil {
+			return MultiOpeningProof{}, err
+		}
+	}
+
+	appendOpenings(transcript, domain, commitments, points, values)
+	r := transcript.ChallengeScalar("r")
+
+	// g(X) = Σ r^i q_i(X)
+	g := make(Polynomial, domain.Cardinality)
+	ri := fr.One()
+	for i := range quotients {
+		for j := range g {
+			var tmp fr.Element
+			tmp.Mul(&ri, &quotients[i][j])
+			g[j].Add(&g[j], &tmp)
+		}
+		ri.Mul(&ri, &r)
+	}
+	D, err := Commit(g, ck, numGoRoutines)
+	if err != nil {
+		return MultiOpeningProof{}, err
+	}
+
+	transcript.AppendPoint("D", D)
+	t := transcript.ChallengeScalar("t")
+
+	// h(X) = Σ r^i / (t - z_i) f_i(X)
+	factors, err := multiOpenFactors(r, t, points)
+	if err != nil {
+		return MultiOpeningProof{}, err
+	}
+	hMinusG := make(Polynomial, domain.Cardinality)
+	for i := range polys {
+		for j := range hMinusG {
+			var tmp fr.Element
+			tmp.Mul(&factors[i], &polys[i][j])
+			hMinusG[j].Add(&hMinusG[j], &tmp)
+		}
+	}
+	for j := range hMinusG {
+		hMinusG[j].Sub(&hMinusG[j], &g[j])
+	}
+
+	// Open (h - g) at t
+	proof, err := Open(domain, hMinusG, t, ck, numGoRoutines)
+	if err != nil {
+		return MultiOpeningProof{}, err
+	}
+
+	return MultiOpeningProof{
+		D:                  *D,
+		QuotientCommitment: proof.QuotientCommitment,
+		InputPoints:        points,
+		ClaimedValues:      values,
+	}, nil
+}
+
+// MultiVerify verifies a [MultiOpeningProof] made by [MultiOpen] against the commitments.
+//
+// Returns `nil` if verification was successful, an error otherwise.
+func MultiVerify(domain *Domain, commitments []Commitment, proof *MultiOpeningProof, openKey *OpeningKey) error {
+	return MultiVerifyWithTranscript(NewTranscript(DomSepMultiOpen), domain, commitments, proof, openKey)
+}
+
+// MultiVerifyWithTranscript verifies a [MultiOpeningProof] made by [MultiOpenWithTranscript], transcript
+// must be in the state the prover's was in when given to it.
+func MultiVerifyWithTranscript(transcript *Transcript, domain *Domain, commitments []Commitment, proof *MultiOpeningProof, openKey *OpeningKey) error {
+	E, opening, err := ReduceMultiOpening(transcript, domain, commitments, proof)
+	if err != nil {
+		return err
+	}
+	return Verify(&E, &opening, openKey)
+}
+
+// ReduceMultiOpening reduces a [MultiOpeningProof] to the single opening of E - D at t that
+// [MultiVerifyWithTranscript] checks, so that many multiproofs can be batched with [BatchVerifyMultiPoints].
+func ReduceMultiOpening(transcript *Transcript, domain *Domain, commitments []Commitment, proof *MultiOpeningProof) (Commitment, OpeningProof, error) {
+	if len(commitments) != len(proof.InputPoints) || len(commitments) != len(proof.ClaimedValues) {
+		return Commitment{}, OpeningProof{}, ErrInvalidNumDigests
+	}
+	if len(commitments) == 0 {
+		return Commitment{}, OpeningProof{}, ErrInvalidIndices
+	}
+
+	appendOpenings(transcript, domain, commitments, proof.InputPoints, proof.ClaimedValues)
+	r := transcript.ChallengeScalar("r")
+	transcript.AppendPoint("D", &proof.D)
+	t := transcript.ChallengeScalar("t")
+
+	factors, err := multiOpenFactors(r, t, proof.InputPoints)
+	if err != nil {
+		return Commitment{}, OpeningProof{}, err
+	}
+
+	// E = Σ r^i / (t - z_i) C_i and (h - g)(t) = Σ r^i / (t - z_i) y_i
+	var E bls12381.G1Affine
+	if _, err := E.MultiExp(commitments, factors, ecc.MultiExpConfig{}); err != nil {
+		return Commitment{}, OpeningProof{}, err
+	}
+	var y fr.Element
+	for i := range factors {
+		var tmp fr.Element
+		tmp.Mul(&factors[i], &proof.ClaimedValues[i])
+		y.Add(&y, &tmp)
+	}
+
+	E.Sub(&E, &proof.D)
+	return E, OpeningProof{
+		QuotientCommitment: proof.QuotientCommitment,
+		InputPoint:         t,
+		ClaimedValue:       y,
+	}, nil
+}
+
+// appendOpenings absorbs the public inputs of a multiproof: the size of the domain,
+// the number of openings and every opening (C_i, z_i, y_i).
+func appendOpenings(transcript *Transcript, domain *Domain, commitments []Commitment, points, values []fr.Element) {
+	transcript.AppendUint64("domain", domain.Cardinality)
+	transcript.AppendUint64("openings", uint64(len(commitments)))
+	for i := range commitments {
+		transcript.AppendPoint("C", &commitments[i])
+		transcript.AppendScalar("z", &points[i])
+		transcript.AppendScalar("y", &values[i])
+	}
+}
+
+// multiOpenFactors returns r^i / (t - z_i) for every point z_i.
+func multiOpenFactors(r, t fr.Element, points []fr.Element) ([]fr.Element, error) {
+	factors := make([]fr.Element, len(points))
+	for i := range points {
+		factors[i].Sub(&t, &points[i])
+		// t collides with an opening point with negligible probability
+		if factors[i].IsZero() {
+			return nil, ErrVerifyOpeningProof
+		}
+	}
+	factors = fr.BatchInvert(factors)
+
+	ri := fr.One()
+	for i := range factors {
+		factors[i].Mul(&factors[i], &ri)
+		ri.Mul(&ri, &r)
+	}
+	return factors, nil
+}
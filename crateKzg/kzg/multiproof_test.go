@@ -0,0 +1,84 @@
+package kzg
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiOpenVerify(t *testing.T) {
+	domain := NewDomain(16)
+	srs, _ := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+
+	polys := []Polynomial{randPoly(t, *domain), randPoly(t, *domain), randPoly(t, *domain)}
+	polys = append(polys, polys[0])
+	commitments := make([]Commitment, len(polys))
+	for i := range polys {
+		c, err := Commit(polys[i], &srs.CommitKey, 0)
+		require.NoError(t, err)
+		commitments[i] = *c
+	}
+
+	// Mix points inside and outside of the domain, the same polynomial is opened twice
+	points := []fr.Element{
+		domain.Roots[3],
+		randomScalarNotInDomain(t, *domain),
+		domain.Roots[0],
+		randomScalarNotInDomain(t, *domain),
+	}
+
+	proof, err := MultiOpen(domain, polys, commitments, points, &srs.CommitKey, 0)
+	require.NoError(t, err)
+	require.Equal(t, polys[0][3], proof.ClaimedValues[0])
+	require.NoError(t, MultiVerify(domain, commitments, &proof, &srs.OpeningKey))
+
+	// Wrong claimed value
+	bad := proof
+	bad.ClaimedValues = append([]fr.Element{}, proof.ClaimedValues...)
+	bad.ClaimedValues[1].Add(&bad.ClaimedValues[1], &domain.Roots[1])
+	require.Error(t, MultiVerify(domain, commitments, &bad, &srs.OpeningKey))
+
+	// Wrong commitment
+	swapped := append([]Commitment{}, commitments...)
+	swapped[1], swapped[2] = swapped[2], swapped[1]
+	require.Error(t, MultiVerify(domain, swapped, &proof, &srs.OpeningKey))
+
+	// Mismatched lengths
+	_, err = MultiOpen(domain, polys, commitments[:1], points, &srs.CommitKey, 0)
+	require.ErrorIs(t, err, ErrInvalidNumDigests)
+	require.ErrorIs(t, MultiVerify(domain, commitments[:1], &proof, &srs.OpeningKey), ErrInvalidNumDigests)
+}
+
+func TestMultiOpenWithTranscript(t *testing.T) {
+	domain := NewDomain(16)
+	srs, _ := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+
+	polys := []Polynomial{randPoly(t, *domain), randPoly(t, *domain)}
+	commitments := make([]Commitment, len(polys))
+	for i := range polys {
+		c, err := Commit(polys[i], &srs.CommitKey, 0)
+		require.NoError(t, err)
+		commitments[i] = *c
+	}
+	points := []fr.Element{domain.Roots[2], randomScalarNotInDomain(t, *domain)}
+
+	// the caller binds its own context before the openings
+	transcript := func(key uint64) *Transcript {
+		tr := NewTranscript("TEST_STRUCTURE_")
+		tr.AppendUint64("key", key)
+		return tr
+	}
+	proof, err := MultiOpenWithTranscript(transcript(7), domain, polys, commitments, points, &srs.CommitKey, 0)
+	require.NoError(t, err)
+	require.NoError(t, MultiVerifyWithTranscript(transcript(7), domain, commitments, &proof, &srs.OpeningKey))
+	require.NoError(t, NewPreparedOpeningKey(&srs.OpeningKey).MultiVerifyWithTranscript(transcript(7), domain, commitments, &proof))
+	require.Error(t, MultiVerifyWithTranscript(transcript(8), domain, commitments, &proof, &srs.OpeningKey))
+	require.Error(t, MultiVerify(domain, commitments, &proof, &srs.OpeningKey))
+
+	// the reduced opening is a plain KZG opening
+	E, opening, err := ReduceMultiOpening(transcript(7), domain, commitments, &proof)
+	require.NoError(t, err)
+	require.NoError(t, Verify(&E, &opening, &srs.OpeningKey))
+}
@@ -0,0 +1,301 @@
+package kzg
+
+import (
+	"crypto/rand"
+	"io"
+	"math/big"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// loopCounter holds the bits of |x₀| = 0xd201000000010000, the BLS12-381 seed, least significant first.
+var loopCounter = [64]int8{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 1, 0, 1, 1}
+
+// lineEvaluation is a line of the Miller loop, before its evaluation at a G1 point:
+// ℓ(P) = r0 + r1 P.x + r2 P.y
+type lineEvaluation struct {
+	r0, r1, r2 bls12381.E2
+}
+
+// g2Lines are the lines of the Miller loop of a fixed G2 point, indexed by the bit of loopCounter.
+// add[i] is only set when loopCounter[i] == 1.
+type g2Lines struct {
+	double [len(loopCounter) - 1]lineEvaluation
+	add    [len(loopCounter) - 1]lineEvaluation
+}
+
+// PreparedOpeningKey is an [OpeningKey] with the Miller loop lines of GenG2 and AlphaG2 precomputed.
+//
+// Those lines only depend on the G2 points, so verifying many proofs with the same key skips
+// all of the G2 arithmetic of the pairings. gnark-crypto v0.11 has no fixed-argument pairing,
+// the precomputation follows its MillerLoop. See [fixed-argument].
+//
+// [fixed-argument]: https://eprint.iacr.org/2010/354
+type PreparedOpeningKey struct {
+	OpeningKey
+	genG2Lines   g2Lines
+	alphaG2Lines g2Lines
+}
+
+// NewPreparedOpeningKey precomputes the lines of openKey. The G2 points must not be the identity.
+func NewPreparedOpeningKey(openKey *OpeningKey) *PreparedOpeningKey {
+	return &PreparedOpeningKey{
+		OpeningKey:   *openKey,
+		genG2Lines:   precomputeLines(&openKey.GenG2),
+		alphaG2Lines: precomputeLines(&openKey.AlphaG2),
+	}
+}
+
+// Verify a single KZG proof like [Verify], with the fixed-argument pairing.
+//
+// [α - z]G₂ depends on the proof, so the check is rearranged to only pair against the fixed points:
+//
+//	e([f(α) - f(z)]G₁ + [z]π, G₂) == e(π, [α]G₂)
+func (pk *PreparedOpeningKey) Verify(commitment *Commitment, proof *OpeningProof) error {
+	// [f(α) - f(z) + z q(α)]G₁
+	var lhs, tmp bls12381.G1Jac
+	var bi big.Int
+	lhs.FromAffine(commitment)
+	proof.ClaimedValue.BigInt(&bi)
+	tmp.ScalarMultiplicationAffine(&pk.GenG1, &bi)
+	lhs.SubAssign(&tmp)
+	proof.InputPoint.BigInt(&bi)
+	tmp.ScalarMultiplicationAffine(&proof.QuotientCommitment, &bi)
+	lhs.AddAssign(&tmp)
+
+	var lhsAff, negQuotient bls12381.G1Affine
+	lhsAff.FromJacobian(&lhs)
+	negQuotient.Neg(&proof.QuotientCommitment)
+
+	return pk.pairingCheck(lhsAff, negQuotient)
+}
+
+// MultiVerifyWithTranscript verifies a [MultiOpeningProof] like [MultiVerifyWithTranscript], with the
+// fixed-argument pairing.
+func (pk *PreparedOpeningKey) MultiVerifyWithTranscript(transcript *Transcript, domain *Domain, commitments []Commitment, proof *MultiOpeningProof) error {
+	E, opening, err := ReduceMultiOpening(transcript, domain, commitments, proof)
+	if err != nil {
+		return err
+	}
+	return pk.Verify(&E, &opening)
+}
+
+// BatchVerifyMultiPoints verifies multiple KZG proofs like [BatchVerifyMultiPoints], with the
+// fixed-argument pairing. Randomness is read from the OS.
+func (pk *PreparedOpeningKey) BatchVerifyMultiPoints(commitments []Commitment, proofs []OpeningProof) error {
+	return pk.BatchVerifyMultiPointsWithReader(commitments, proofs, rand.Reader)
+}
+
+// BatchVerifyMultiPointsWithReader is [PreparedOpeningKey.BatchVerifyMultiPoints] with the randomness
+// read from reader, like [BatchVerifyMultiPointsWithReader].
+func (pk *PreparedOpeningKey) BatchVerifyMultiPointsWithReader(commitments []Commitment, proofs []OpeningProof, reader io.Reader) error {
+	return pk.batchVerifyMultiPoints(commitments, proofs, func() (fr.Element, error) {
+		return randomScalar(reader)
+	})
+}
+
+// BatchVerifyMultiPointsDeterministic is [PreparedOpeningKey.BatchVerifyMultiPoints] with the
+// batching scalar of [BatchVerifyMultiPointsDeterministic].
+func (pk *PreparedOpeningKey) BatchVerifyMultiPointsDeterministic(commitments []Commitment, proofs []OpeningProof) error {
+	return pk.batchVerifyMultiPoints(commitments, proofs, transcriptScalar(commitments, proofs, &pk.OpeningKey))
+}
+
+// batchVerifyMultiPoints is [batchVerifyMultiPoints] with the fixed-argument pairing.
+func (pk *PreparedOpeningKey) batchVerifyMultiPoints(commitments []Commitment, proofs []OpeningProof, sample func() (fr.Element, error)) error {
+	if len(commitments) != len(proofs) {
+		return ErrInvalidNumDigests
+	}
+	if len(commitments) == 0 {
+		return nil
+	}
+	if len(commitments) == 1 {
+		return pk.Verify(&commitments[0], &proofs[0])
+	}
+
+	lhs, negQuotients, err := foldProofs(commitments, proofs, &pk.GenG1, sample)
+	if err != nil {
+		return err
+	}
+	return pk.pairingCheck(lhs, negQuotients)
+}
+
+// pairingCheck checks that e(p, G₂) e(q, [α]G₂) == 1.
+func (pk *PreparedOpeningKey) pairingCheck(p, q bls12381.G1Affine) error {
+	ml := millerLoopFixedQ(
+		[]bls12381.G1Affine{p, q},
+		[]*g2Lines{&pk.genG2Lines, &pk.alphaG2Lines},
+	)
+	check := bls12381.FinalExponentiation(&ml)
+	if !check.IsOne() {
+		return ErrVerifyOpeningProof
+	}
+	return nil
+}
+
+// precomputeLines runs the Miller loop of q, keeping its lines instead of evaluating them.
+func precomputeLines(q *bls12381.G2Affine) g2Lines {
+	var lines g2Lines
+	var qProj g2Proj
+	qProj.fromAffine(q)
+
+	for i := len(loopCounter) - 2; i >= 1; i-- {
+		qProj.doubleStep(&lines.double[i])
+		if loopCounter[i] == 1 {
+			qProj.addMixedStep(&lines.add[i], q)
+		}
+	}
+	// loopCounter[0] = 0, the last doubling is not needed
+	qProj.tangentLine(&lines.double[0])
+
+	return lines
+}
+
+// millerLoopFixedQ computes ∏ₖ f_{x₀,Qₖ}(Pₖ) with the precomputed lines of every Qₖ.
+func millerLoopFixedQ(p []bls12381.G1Affine, lines []*g2Lines) bls12381.GT {
+	var result bls12381.GT
+	result.SetOne()
+
+	var l lineEvaluation
+	for i := len(loopCounter) - 2; i >= 0; i-- {
+		result.Square(&result)
+		for k := range p {
+			// ℓ(P) = 1 when P is the identity
+			if p[k].IsInfinity() {
+				continue
+			}
+			l.evaluate(&lines[k].double[i], &p[k])
+			result.MulBy014(&l.r0, &l.r1, &l.r2)
+			if loopCounter[i] == 1 {
+				l.evaluate(&lines[k].add[i], &p[k])
+				result.MulBy014(&l.r0, &l.r1, &l.r2)
+			}
+		}
+	}
+
+	// negative x₀
+	result.Conjugate(&result)
+
+	return result
+}
+
+// evaluate sets l to the line evaluated at p.
+func (l *lineEvaluation) evaluate(line *lineEvaluation, p *bls12381.G1Affine) {
+	l.r0.Set(&line.r0)
+	l.r1.MulByElement(&line.r1, &p.X)
+	l.r2.MulByElement(&line.r2, &p.Y)
+}
+
+// g2Proj is a G2 point in homogeneous projective coordinates.
+type g2Proj struct {
+	x, y, z bls12381.E2
+}
+
+func (p *g2Proj) fromAffine(q *bls12381.G2Affine) {
+	p.x.Set(&q.X)
+	p.y.Set(&q.Y)
+	p.z.SetOne()
+}
+
+// doubleStep doubles a point in Homogenous projective coordinates, and evaluates the line in Miller loop
+// https://eprint.iacr.org/2013/722.pdf (Section 4.3)
+//
+// Copied from gnark-crypto, where it is unexported.
+func (p *g2Proj) doubleStep(l *lineEvaluation) {
+	var t1, A, B, C, D, E, EE, F, G, H, I, J, K bls12381.E2
+	A.Mul(&p.x, &p.y)
+	A.Halve()
+	B.Square(&p.y)
+	C.Square(&p.z)
+	D.Double(&C).
+		Add(&D, &C)
+	E.MulBybTwistCurveCoeff(&D)
+	F.Double(&E).
+		Add(&F, &E)
+	G.Add(&B, &F)
+	G.Halve()
+	H.Add(&p.y, &p.z).
+		Square(&H)
+	t1.Add(&B, &C)
+	H.Sub(&H, &t1)
+	I.Sub(&E, &B)
+	J.Square(&p.x)
+	EE.Square(&E)
+	K.Double(&EE).
+		Add(&K, &EE)
+
+	// X, Y, Z
+	p.x.Sub(&B, &F).
+		Mul(&p.x, &A)
+	p.y.Square(&G).
+		Sub(&p.y, &K)
+	p.z.Mul(&B, &H)
+
+	// Line evaluation
+	l.r0.Set(&I)
+	l.r1.Double(&J).
+		Add(&l.r1, &J)
+	l.r2.Neg(&H)
+}
+
+// addMixedStep point addition in Mixed Homogenous projective and Affine coordinates
+// https://eprint.iacr.org/2013/722.pdf (Section 4.3)
+//
+// Copied from gnark-crypto, where it is unexported.
+func (p *g2Proj) addMixedStep(l *lineEvaluation, a *bls12381.G2Affine) {
+	var Y2Z1, X2Z1, O, L, C, D, E, F, G, H, t0, t1, t2, J bls12381.E2
+	Y2Z1.Mul(&a.Y, &p.z)
+	O.Sub(&p.y, &Y2Z1)
+	X2Z1.Mul(&a.X, &p.z)
+	L.Sub(&p.x, &X2Z1)
+	C.Square(&O)
+	D.Square(&L)
+	E.Mul(&L, &D)
+	F.Mul(&p.z, &C)
+	G.Mul(&p.x, &D)
+	t0.Double(&G)
+	H.Add(&E, &F).
+		Sub(&H, &t0)
+	t1.Mul(&p.y, &E)
+
+	// X, Y, Z
+	p.x.Mul(&L, &H)
+	p.y.Sub(&G, &H).
+		Mul(&p.y, &O).
+		Sub(&p.y, &t1)
+	p.z.Mul(&E, &p.z)
+
+	t2.Mul(&L, &a.Y)
+	J.Mul(&a.X, &O).
+		Sub(&J, &t2)
+
+	// Line evaluation
+	l.r0.Set(&J)
+	l.r1.Neg(&O)
+	l.r2.Set(&L)
+}
+
+// tangentLine computes the tangent through [2]p in Homogenous projective coordinates.
+// It does not compute the resulting point [2]p.
+//
+// Copied from gnark-crypto, where it is unexported.
+func (p *g2Proj) tangentLine(l *lineEvaluation) {
+	var t1, B, C, D, E, H, I, J bls12381.E2
+	B.Square(&p.y)
+	C.Square(&p.z)
+	D.Double(&C).
+		Add(&D, &C)
+	E.MulBybTwistCurveCoeff(&D)
+	H.Add(&p.y, &p.z).
+		Square(&H)
+	t1.Add(&B, &C)
+	H.Sub(&H, &t1)
+	I.Sub(&E, &B)
+	J.Square(&p.x)
+
+	// Line evaluation
+	l.r0.Set(&I)
+	l.r1.Double(&J).
+		Add(&l.r1, &J)
+	l.r2.Neg(&H)
+}
@@ -0,0 +1,83 @@
+package kzg
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMillerLoopFixedQ(t *testing.T) {
+	_, _, g1, g2 := bls12381.Generators()
+	var p1, p2 bls12381.G1Affine
+	var q2 bls12381.G2Affine
+	p1.ScalarMultiplication(&g1, big.NewInt(5))
+	p2.ScalarMultiplication(&g1, big.NewInt(11))
+	q2.ScalarMultiplication(&g2, big.NewInt(7))
+
+	p := []bls12381.G1Affine{p1, p2, {}}
+	q := []bls12381.G2Affine{g2, q2, g2}
+	want, err := bls12381.Pair(p, q)
+	require.NoError(t, err)
+
+	lines := []*g2Lines{}
+	for i := range q {
+		l := precomputeLines(&q[i])
+		lines = append(lines, &l)
+	}
+	ml := millerLoopFixedQ(p, lines)
+	got := bls12381.FinalExponentiation(&ml)
+	require.True(t, got.Equal(&want))
+}
+
+func TestPreparedOpeningKey(t *testing.T) {
+	domain := NewDomain(4)
+	srs, _ := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+	pk := NewPreparedOpeningKey(&srs.OpeningKey)
+
+	numProofs := 5
+	commitments := make([]Commitment, 0, numProofs)
+	proofs := make([]OpeningProof, 0, numProofs)
+	for i := 0; i < numProofs; i++ {
+		proof, commitment := randValidOpeningProof(t, *domain, *srs)
+		require.NoError(t, pk.Verify(&commitment, &proof))
+		commitments = append(commitments, commitment)
+		proofs = append(proofs, proof)
+	}
+	require.NoError(t, pk.BatchVerifyMultiPoints(commitments, proofs))
+
+	// A wrong claimed value
+	proofs[2].ClaimedValue.Add(&proofs[2].ClaimedValue, &domain.Roots[1])
+	require.ErrorIs(t, pk.Verify(&commitments[2], &proofs[2]), ErrVerifyOpeningProof)
+	require.Error(t, pk.BatchVerifyMultiPoints(commitments, proofs))
+}
+
+func TestPreparedOpeningKeyInjectedRandomness(t *testing.T) {
+	domain := NewDomain(4)
+	srs, _ := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+	pk := NewPreparedOpeningKey(&srs.OpeningKey)
+
+	proof0, commitment0 := randValidOpeningProof(t, *domain, *srs)
+	proof1, commitment1 := randValidOpeningProof(t, *domain, *srs)
+	commitments := []Commitment{commitment0, commitment1}
+	proofs := []OpeningProof{proof0, proof1}
+	require.NoError(t, pk.BatchVerifyMultiPointsDeterministic(commitments, proofs))
+
+	// The same forgery as in TestBatchVerifyInjectedRandomness, against r = 7
+	scalar := make([]byte, 64)
+	scalar[63] = 7
+	r := fr.NewElement(7)
+	delta := fr.NewElement(3)
+	var shift fr.Element
+	shift.Div(&delta, &r)
+	proofs[0].ClaimedValue.Add(&proofs[0].ClaimedValue, &delta)
+	proofs[1].ClaimedValue.Sub(&proofs[1].ClaimedValue, &shift)
+
+	require.NoError(t, pk.BatchVerifyMultiPointsWithReader(commitments, proofs, bytes.NewReader(scalar)))
+	require.Error(t, pk.BatchVerifyMultiPointsDeterministic(commitments, proofs))
+	require.Error(t, pk.BatchVerifyMultiPoints(commitments, proofs))
+	require.Error(t, pk.BatchVerifyMultiPointsWithReader(commitments, proofs, bytes.NewReader(nil)))
+}
@@ -11,6 +11,28 @@ import (
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 )
 
+// NewInsecureDevSRS creates an SRS from the secret `bAlpha`, both in monomial basis and in
+// Lagrange basis over domain.
+//
+// INSECURE: whoever knows the secret can open a commitment to any value. This is meant for
+// local devnets and tests only, never use it to commit to anything of value.
+func NewInsecureDevSRS(domain *Domain, bAlpha *big.Int) (monomial *SRS, lagrange *SRS, err error) {
+	var alpha fr.Element
+	if alpha.SetBigInt(bAlpha).IsZero() {
+		return nil, nil, ErrZeroSecret
+	}
+	monomial, err = newMonomialSRSInsecureUint64(domain.Cardinality, bAlpha)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lagrange = &SRS{
+		CommitKey:  CommitKey{G1: domain.IfftG1(monomial.CommitKey.G1)},
+		OpeningKey: monomial.OpeningKey,
+	}
+	return monomial, lagrange, nil
+}
+
 // newLagrangeSRSInsecure creates a new SRS object with the secret `bAlpha`.
 // The resulting SRS is in Lagrange basis.
 //
@@ -43,3 +43,17 @@ func TestCommitRegression(t *testing.T) {
 	expectedCommitment := "85bdf872da5b8561d23055d32db3fc86c672b0be7543b8c1e48634af07231bf7ab6385b765750921017cbcdbcd14f8e0"
 	require.Equal(t, expectedCommitment, gotCommitment)
 }
+
+func TestInsecureDevSRS(t *testing.T) {
+	domain := NewDomain(8)
+	monomial, lagrange, err := NewInsecureDevSRS(domain, big.NewInt(100))
+	require.NoError(t, err)
+
+	srsLagrange, _ := newLagrangeSRSInsecure(*domain, big.NewInt(100))
+	require.Equal(t, srsLagrange, lagrange)
+	srsMonomial, _ := newMonomialSRSInsecure(*domain, big.NewInt(100))
+	require.Equal(t, srsMonomial, monomial)
+
+	_, _, err = NewInsecureDevSRS(domain, new(big.Int).Set(fr.Modulus()))
+	require.ErrorIs(t, err, ErrZeroSecret)
+}
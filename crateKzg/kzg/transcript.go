@@ -0,0 +1,89 @@
+package kzg
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// Transcript is a Fiat–Shamir transcript: the prover and the verifier append the same
+// public inputs in the same order, and derive challenges from everything appended so far.
+//
+// Every message is prefixed with its label and its length, so that two different
+// sequences of messages can never produce the same input to the hash.
+type Transcript struct {
+	state hash.Hash
+}
+
+// NewTranscript returns a transcript for the protocol named by label.
+func NewTranscript(label string) *Transcript {
+	t := &Transcript{state: sha256.New()}
+	t.appendMessage("protocol", []byte(label))
+	return t
+}
+
+func (t *Transcript) appendMessage(label string, msg []byte) {
+	var size [8]byte
+	binary.BigEndian.PutUint64(size[:], uint64(len(label)))
+	t.state.Write(size[:])
+	t.state.Write([]byte(label))
+	binary.BigEndian.PutUint64(size[:], uint64(len(msg)))
+	t.state.Write(size[:])
+	t.state.Write(msg)
+}
+
+// AppendBytes appends an arbitrary message.
+func (t *Transcript) AppendBytes(label string, msg []byte) {
+	t.appendMessage(label, msg)
+}
+
+// AppendUint64 appends an integer, such as a size or an index.
+func (t *Transcript) AppendUint64(label string, n uint64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], n)
+	t.appendMessage(label, b[:])
+}
+
+// AppendScalar appends a field element in its canonical big-endian form.
+func (t *Transcript) AppendScalar(label string, s *fr.Element) {
+	b := s.Bytes()
+	t.appendMessage(label, b[:])
+}
+
+// AppendScalars appends field elements, one message per element.
+func (t *Transcript) AppendScalars(label string, s []fr.Element) {
+	for i := range s {
+		t.AppendScalar(label, &s[i])
+	}
+}
+
+// AppendPoint appends a G1 element in its compressed form.
+func (t *Transcript) AppendPoint(label string, p *bls12381.G1Affine) {
+	b := p.Bytes()
+	t.appendMessage(label, b[:])
+}
+
+// AppendPoints appends G1 elements, one message per element.
+func (t *Transcript) AppendPoints(label string, p []bls12381.G1Affine) {
+	for i := range p {
+		t.AppendPoint(label, &p[i])
+	}
+}
+
+// ChallengeScalar derives a challenge from everything appended so far.
+//
+// The challenge is itself appended to the transcript, so later challenges depend on it.
+func (t *Transcript) ChallengeScalar(label string) fr.Element {
+	t.appendMessage(label, nil)
+	digest := t.state.Sum(nil)
+
+	t.state.Reset()
+	t.appendMessage("challenge", digest)
+
+	var challenge fr.Element
+	challenge.SetBytes(digest)
+	return challenge
+}
@@ -0,0 +1,61 @@
+package kzg
+
+import (
+	"testing"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+func TestTranscriptDeterministic(t *testing.T) {
+	_, _, g1, _ := bls12381.Generators()
+	s := fr.NewElement(7)
+
+	challenge := func() (fr.Element, fr.Element) {
+		tr := NewTranscript("test")
+		tr.AppendPoint("C", &g1)
+		tr.AppendScalar("z", &s)
+		r := tr.ChallengeScalar("r")
+		tr.AppendUint64("n", 3)
+		return r, tr.ChallengeScalar("t")
+	}
+
+	r1, t1 := challenge()
+	r2, t2 := challenge()
+	if !r1.Equal(&r2) || !t1.Equal(&t2) {
+		t.Fatal("same transcript produced different challenges")
+	}
+	if r1.Equal(&t1) {
+		t.Fatal("consecutive challenges should differ")
+	}
+}
+
+func TestTranscriptSeparation(t *testing.T) {
+	s := fr.NewElement(7)
+
+	tr1 := NewTranscript("test")
+	tr1.AppendScalar("a", &s)
+	c1 := tr1.ChallengeScalar("r")
+
+	// Different label
+	tr2 := NewTranscript("test")
+	tr2.AppendScalar("b", &s)
+	c2 := tr2.ChallengeScalar("r")
+
+	// Different protocol
+	tr3 := NewTranscript("other")
+	tr3.AppendScalar("a", &s)
+	c3 := tr3.ChallengeScalar("r")
+
+	// Same bytes, split differently between label and message
+	tr4 := NewTranscript("test")
+	tr4.AppendBytes("ab", []byte("c"))
+	c4 := tr4.ChallengeScalar("r")
+	tr5 := NewTranscript("test")
+	tr5.AppendBytes("a", []byte("bc"))
+	c5 := tr5.ChallengeScalar("r")
+
+	if c1.Equal(&c2) || c1.Equal(&c3) || c4.Equal(&c5) {
+		t.Fatal("distinct transcripts produced the same challenge")
+	}
+}
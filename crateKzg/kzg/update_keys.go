@@ -0,0 +1,164 @@
+package kzg
+
+import (
+	"math/big"
+	"sync"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// UpdateKeys keeps opening proofs at points of the domain valid when the committed polynomial changes,
+// following the update keys of aSVC. See [asvc].
+//
+// Let f(X) be a polynomial in lagrange form and π_i the proof of f at w_i, a commitment to
+// q_i(X) = (f(X) - f(w_i)) / (X - w_i). If the evaluation of f at w_j changes by δ, then f changes
+// by δ L_j(X) and:
+//
+//   - For i == j, q_i changes by δ (L_i(X) - 1) / (X - w_i). A commitment to the latter is the
+//     update key u_i, it depends on the setup only.
+//   - For i != j, q_i changes by δ L_j(X) / (X - w_i). Splitting L_j(X) = (w_j/n) (X^n - 1) / (X - w_j)
+//     into partial fractions gives δ / (w_j - w_i) ([L_j(α)]G₁ - (w_j/w_i) [L_i(α)]G₁), which only
+//     needs the lagrange setup.
+//
+// Either way a proof is updated with O(1) group operations.
+//
+// [asvc]: https://eprint.iacr.org/2020/527
+type UpdateKeys struct {
+	domain *Domain
+	ck     *CommitKey
+
+	mu sync.Mutex
+	// u caches the update keys computed so far, by index in the domain
+	u map[uint64]bls12381.G1Affine
+}
+
+// NewUpdateKeys returns the update keys for polynomials over domain committed with ck.
+//
+// The keys are computed lazily, for the indices that actually change.
+func NewUpdateKeys(domain *Domain, ck *CommitKey) *UpdateKeys {
+	return &UpdateKeys{
+		domain: domain,
+		ck:     ck,
+		u:      make(map[uint64]bls12381.G1Affine),
+	}
+}
+
+// U returns the update key u_j, a commitment to (L_j(X) - 1) / (X - w_j).
+//
+// This is the opening proof of L_j at w_j, computed on first use with [Open].
+func (k *UpdateKeys) U(j uint64) (bls12381.G1Affine, error) {
+	if j >= k.domain.Cardinality {
+		return bls12381.G1Affine{}, ErrPolynomialMismatchedSizeDomain
+	}
+
+	k.mu.Lock()
+	u, ok := k.u[j]
+	k.mu.Unlock()
+	if ok {
+		return u, nil
+	}
+
+	lagrange := make(Polynomial, k.domain.Cardinality)
+	lagrange[j].SetOne()
+	proof, err := Open(k.domain, lagrange, k.domain.Roots[j], k.ck, 0)
+	if err != nil {
+		return bls12381.G1Affine{}, err
+	}
+
+	k.mu.Lock()
+	k.u[j] = proof.QuotientCommitment
+	k.mu.Unlock()
+	return proof.QuotientCommitment, nil
+}
+
+// UpdateProof adjusts the proof at index i after the evaluation at index j changed by delta.
+func (k *UpdateKeys) UpdateProof(proof *bls12381.G1Affine, i, j uint64, delta fr.Element) error {
+	n := k.domain.Cardinality
+	if i >= n || j >= n {
+		return ErrPolynomialMismatchedSizeDomain
+	}
+
+	var scalarBI big.Int
+	if i == j {
+		u, err := k.U(j)
+		if err != nil {
+			return err
+		}
+		delta.BigInt(&scalarBI)
+
+		var tmp bls12381.G1Affine
+		tmp.ScalarMultiplication(&u, &scalarBI)
+		proof.Add(proof, &tmp)
+		return nil
+	}
+
+	// δ / (w_j - w_i)
+	var factor fr.Element
+	factor.Sub(&k.domain.Roots[j], &k.domain.Roots[i])
+	factor.Inverse(&factor)
+	factor.Mul(&factor, &delta)
+
+	// δ / (w_j - w_i) * w_j / w_i
+	var factorI fr.Element
+	factorI.Mul(&factor, &k.domain.Roots[j])
+	factorI.Mul(&factorI, &k.domain.PreComputedInverses[i])
+
+	var termJ, termI bls12381.G1Jac
+	factor.BigInt(&scalarBI)
+	termJ.ScalarMultiplicationAffine(&k.ck.G1[j], &scalarBI)
+	factorI.BigInt(&scalarBI)
+	termI.ScalarMultiplicationAffine(&k.ck.G1[i], &scalarBI)
+	termJ.SubAssign(&termI)
+	termJ.AddMixed(proof)
+
+	proof.FromJacobian(&termJ)
+	return nil
+}
+
+// UpdateProofs adjusts the proofs at every index of the domain after the evaluation at index j changed by delta.
+//
+// proofs[i] is the proof at domain.Roots[i].
+func (k *UpdateKeys) UpdateProofs(proofs []bls12381.G1Affine, j uint64, delta fr.Element) error {
+	n := k.domain.Cardinality
+	if uint64(len(proofs)) != n || j >= n {
+		return ErrPolynomialMismatchedSizeDomain
+	}
+
+	// The [L_j(α)]G₁ terms share their base, so they are computed together.
+	factors := make([]fr.Element, n)
+	for i := uint64(0); i < n; i++ {
+		if i == j {
+			continue
+		}
+		factors[i].Sub(&k.domain.Roots[j], &k.domain.Roots[i])
+	}
+	factors = fr.BatchInvert(factors)
+	for i := range factors {
+		factors[i].Mul(&factors[i], &delta)
+	}
+	// Note: factors[j] is zero, the gnark-crypto library leaves zeroes untouched when inverting.
+	termsJ := bls12381.BatchScalarMultiplicationG1(&k.ck.G1[j], factors)
+
+	updated := make([]bls12381.G1Jac, n)
+	for i := uint64(0); i < n; i++ {
+		updated[i].FromAffine(&proofs[i])
+		if i == j {
+			continue
+		}
+
+		var factorI fr.Element
+		var factorIBI big.Int
+		factorI.Mul(&factors[i], &k.domain.Roots[j])
+		factorI.Mul(&factorI, &k.domain.PreComputedInverses[i])
+		factorI.BigInt(&factorIBI)
+
+		var termI bls12381.G1Jac
+		termI.ScalarMultiplicationAffine(&k.ck.G1[i], &factorIBI)
+		updated[i].SubAssign(&termI)
+		updated[i].AddMixed(&termsJ[i])
+	}
+	copy(proofs, bls12381.BatchJacobianToAffineG1(updated))
+
+	return k.UpdateProof(&proofs[j], j, j, delta)
+}
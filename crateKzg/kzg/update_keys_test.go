@@ -0,0 +1,60 @@
+package kzg
+
+import (
+	"math/big"
+	"testing"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+func TestUpdateKeysUpdateProofs(t *testing.T) {
+	domain := NewDomain(16)
+	srs, _ := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+	keys := NewUpdateKeys(domain, &srs.CommitKey)
+
+	allProofs := func(poly Polynomial) []bls12381.G1Affine {
+		proofs := make([]bls12381.G1Affine, domain.Cardinality)
+		for i := range proofs {
+			proof, err := Open(domain, poly, domain.Roots[i], &srs.CommitKey, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			proofs[i] = proof.QuotientCommitment
+		}
+		return proofs
+	}
+
+	poly := randPoly(t, *domain)
+	proofs := allProofs(poly)
+
+	// Change the evaluation at index 5
+	var j uint64 = 5
+	var newValue, delta fr.Element
+	newValue.SetRandom()
+	delta.Sub(&newValue, &poly[j])
+	poly[j] = newValue
+
+	// Update a single proof, on and off the changed index
+	single := []uint64{3, j}
+	for _, i := range single {
+		proof := proofs[i]
+		if err := keys.UpdateProof(&proof, i, j, delta); err != nil {
+			t.Fatal(err)
+		}
+		expected, _ := Open(domain, poly, domain.Roots[i], &srs.CommitKey, 0)
+		if !proof.Equal(&expected.QuotientCommitment) {
+			t.Fatalf("updated proof %d differs from the recomputed one", i)
+		}
+	}
+
+	if err := keys.UpdateProofs(proofs, j, delta); err != nil {
+		t.Fatal(err)
+	}
+	expected := allProofs(poly)
+	for i := range proofs {
+		if !proofs[i].Equal(&expected[i]) {
+			t.Fatalf("updated proof %d differs from the recomputed one", i)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+package poly
+
+import "errors"
+
+var (
+	ErrDivisionByZero  = errors.New("division by the zero polynomial")
+	ErrDuplicatePoints = errors.New("interpolation points are not distinct")
+	ErrMismatchedSizes = errors.New("number of points and values differ")
+	ErrIndexOutOfRange = errors.New("index is not one of the points")
+)
@@ -0,0 +1,289 @@
+package poly
+
+import (
+	"math/bits"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/fft"
+)
+
+// Polynomials in this package are in monomial form: p[i] is the coefficient of X^i.
+// The result of an operation may have trailing zero coefficients, see [Degree].
+
+// fftThreshold is the size of the smaller factor above which [Mul] switches
+// from the schoolbook product to FFTs.
+const fftThreshold = 64
+
+// fftDomains caches the domains of [Mul] by size, building one precomputes its twiddle factors.
+var fftDomains sync.Map
+
+// Degree returns the degree of p, ignoring trailing zero coefficients.
+// The zero polynomial has degree -1.
+func Degree(p []fr.Element) int {
+	for i := len(p) - 1; i >= 0; i-- {
+		if !p[i].IsZero() {
+			return i
+		}
+	}
+	return -1
+}
+
+// Evaluate returns p(x), with Horner's method.
+func Evaluate(p []fr.Element, x fr.Element) fr.Element {
+	var res fr.Element
+	for i := len(p) - 1; i >= 0; i-- {
+		res.Mul(&res, &x)
+		res.Add(&res, &p[i])
+	}
+	return res
+}
+
+// Add returns a + b.
+func Add(a, b []fr.Element) []fr.Element {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	res := make([]fr.Element, len(a))
+	copy(res, a)
+	for i := range b {
+		res[i].Add(&res[i], &b[i])
+	}
+	return res
+}
+
+// Scale returns c * p.
+func Scale(p []fr.Element, c fr.Element) []fr.Element {
+	res := make([]fr.Element, len(p))
+	for i := range p {
+		res[i].Mul(&p[i], &c)
+	}
+	return res
+}
+
+// Derivative returns the formal derivative of p.
+func Derivative(p []fr.Element) []fr.Element {
+	if len(p) <= 1 {
+		return []fr.Element{}
+	}
+	res := make([]fr.Element, len(p)-1)
+	for i := range res {
+		var c fr.Element
+		c.SetUint64(uint64(i + 1))
+		res[i].Mul(&p[i+1], &c)
+	}
+	return res
+}
+
+// Mul returns a * b.
+//
+// Small products are computed with the schoolbook method, larger ones by evaluating both
+// factors on a domain of roots of unity and multiplying pointwise.
+func Mul(a, b []fr.Element) []fr.Element {
+	if len(a) == 0 || len(b) == 0 {
+		return []fr.Element{}
+	}
+	if len(a) < fftThreshold || len(b) < fftThreshold {
+		return mulSchoolbook(a, b)
+	}
+
+	n := len(a) + len(b) - 1
+	domain := fftDomain(uint64(1) << bits.Len(uint(n-1)))
+
+	// the evaluations are in bit-reversed order, which the pointwise product ignores
+	aEvals := padded(a, domain.Cardinality)
+	bEvals := padded(b, domain.Cardinality)
+	domain.FFT(aEvals, fft.DIF)
+	domain.FFT(bEvals, fft.DIF)
+	for i := range aEvals {
+		aEvals[i].Mul(&aEvals[i], &bEvals[i])
+	}
+	domain.FFTInverse(aEvals, fft.DIT)
+	return aEvals[:n]
+}
+
+// fftDomain returns the domain of size roots of unity, building it on first use.
+func fftDomain(size uint64) *fft.Domain {
+	if domain, ok := fftDomains.Load(size); ok {
+		return domain.(*fft.Domain)
+	}
+	domain, _ := fftDomains.LoadOrStore(size, fft.NewDomain(size))
+	return domain.(*fft.Domain)
+}
+
+func mulSchoolbook(a, b []fr.Element) []fr.Element {
+	res := make([]fr.Element, len(a)+len(b)-1)
+	var tmp fr.Element
+	for i := range a {
+		for j := range b {
+			tmp.Mul(&a[i], &b[j])
+			res[i+j].Add(&res[i+j], &tmp)
+		}
+	}
+	return res
+}
+
+// padded returns a copy of p extended with zeroes to n coefficients.
+func padded(p []fr.Element, n uint64) []fr.Element {
+	res := make([]fr.Element, n)
+	copy(res, p)
+	return res
+}
+
+// DivideByLinear returns the quotient q and the remainder r of p divided by (X - z),
+// that is p(X) = q(X) (X - z) + r and r = p(z).
+func DivideByLinear(p []fr.Element, z fr.Element) ([]fr.Element, fr.Element) {
+	if len(p) == 0 {
+		return []fr.Element{}, fr.Element{}
+	}
+
+	// synthetic division, from the leading coefficient down
+	q := make([]fr.Element, len(p)-1)
+	var carry fr.Element
+	for i := len(p) - 1; i >= 1; i-- {
+		carry.Mul(&carry, &z)
+		carry.Add(&carry, &p[i])
+		q[i-1] = carry
+	}
+	carry.Mul(&carry, &z)
+	carry.Add(&carry, &p[0])
+	return q, carry
+}
+
+// DivideByDomainVanishing returns the quotient and the remainder of p divided by X^n - 1,
+// the polynomial vanishing on a domain of n roots of unity. It costs O(len(p)).
+func DivideByDomainVanishing(p []fr.Element, n int) ([]fr.Element, []fr.Element) {
+	if len(p) <= n {
+		rem := make([]fr.Element, len(p))
+		copy(rem, p)
+		return []fr.Element{}, rem
+	}
+
+	// p_i X^i = p_i X^{i-n} (X^n - 1) + p_i X^{i-n}, from the leading coefficient down
+	rem := make([]fr.Element, len(p))
+	copy(rem, p)
+	q := make([]fr.Element, len(p)-n)
+	for i := len(p) - 1; i >= n; i-- {
+		q[i-n] = rem[i]
+		rem[i-n].Add(&rem[i-n], &rem[i])
+	}
+	return q, rem[:n]
+}
+
+// Div returns the quotient q and the remainder r of the long division of a by b,
+// so that a = q b + r with deg(r) < deg(b).
+func Div(a, b []fr.Element) ([]fr.Element, []fr.Element, error) {
+	db := Degree(b)
+	if db < 0 {
+		return nil, nil, ErrDivisionByZero
+	}
+	da := Degree(a)
+	rem := make([]fr.Element, da+1)
+	copy(rem, a)
+	if da < db {
+		return []fr.Element{}, rem, nil
+	}
+
+	var leadInv fr.Element
+	leadInv.Inverse(&b[db])
+	q := make([]fr.Element, da-db+1)
+	for i := da; i >= db; i-- {
+		var c fr.Element
+		c.Mul(&rem[i], &leadInv)
+		q[i-db] = c
+		for j := 0; j <= db; j++ {
+			var tmp fr.Element
+			tmp.Mul(&c, &b[j])
+			rem[i-db+j].Sub(&rem[i-db+j], &tmp)
+		}
+	}
+	return q, rem[:db], nil
+}
+
+// Vanishing returns the monic polynomial Π (X - points[i]).
+//
+// The factors are multiplied pairwise in a product tree, so that large products use [Mul]'s FFTs.
+func Vanishing(points []fr.Element) []fr.Element {
+	if len(points) == 0 {
+		return []fr.Element{fr.One()}
+	}
+	factors := make([][]fr.Element, len(points))
+	for i := range points {
+		var neg fr.Element
+		neg.Neg(&points[i])
+		factors[i] = []fr.Element{neg, fr.One()}
+	}
+	for len(factors) > 1 {
+		next := make([][]fr.Element, 0, (len(factors)+1)/2)
+		for i := 0; i+1 < len(factors); i += 2 {
+			next = append(next, Mul(factors[i], factors[i+1]))
+		}
+		if len(factors)%2 == 1 {
+			next = append(next, factors[len(factors)-1])
+		}
+		factors = next
+	}
+	return factors[0]
+}
+
+// LagrangeBasis returns the polynomial that is 1 at points[index] and 0 at the other points.
+func LagrangeBasis(points []fr.Element, index int) ([]fr.Element, error) {
+	if index < 0 || index >= len(points) {
+		return nil, ErrIndexOutOfRange
+	}
+	weights, vanishing, err := BarycentricWeights(points)
+	if err != nil {
+		return nil, err
+	}
+	basis, _ := DivideByLinear(vanishing, points[index])
+	return Scale(basis, weights[index]), nil
+}
+
+// Interpolate returns the polynomial of degree < len(points) that takes values[i] at points[i].
+//
+// With A(X) = Π (X - x_i), the result is Σ y_i / A'(x_i) * A(X) / (X - x_i), which costs O(n²).
+func Interpolate(points, values []fr.Element) ([]fr.Element, error) {
+	if len(points) != len(values) {
+		return nil, ErrMismatchedSizes
+	}
+	if len(points) == 0 {
+		return []fr.Element{}, nil
+	}
+
+	weights, vanishing, err := BarycentricWeights(points)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]fr.Element, len(points))
+	for i := range points {
+		var c fr.Element
+		c.Mul(&values[i], &weights[i])
+		if c.IsZero() {
+			continue
+		}
+		basis, _ := DivideByLinear(vanishing, points[i])
+		for j := range basis {
+			var tmp fr.Element
+			tmp.Mul(&basis[j], &c)
+			res[j].Add(&res[j], &tmp)
+		}
+	}
+	return res, nil
+}
+
+// BarycentricWeights returns 1 / A'(x_i) for every point, along with A(X) = Π (X - x_i).
+// A'(x_i) is zero exactly when x_i is repeated.
+func BarycentricWeights(points []fr.Element) ([]fr.Element, []fr.Element, error) {
+	vanishing := Vanishing(points)
+	derivative := Derivative(vanishing)
+
+	weights := make([]fr.Element, len(points))
+	for i := range points {
+		weights[i] = Evaluate(derivative, points[i])
+		if weights[i].IsZero() {
+			return nil, nil, ErrDuplicatePoints
+		}
+	}
+	return fr.BatchInvert(weights), vanishing, nil
+}
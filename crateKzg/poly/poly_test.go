@@ -0,0 +1,90 @@
+package poly
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/require"
+)
+
+func randPoly(t *testing.T, n int) []fr.Element {
+	p := make([]fr.Element, n)
+	for i := range p {
+		_, err := p[i].SetRandom()
+		require.NoError(t, err)
+	}
+	return p
+}
+
+func TestMul(t *testing.T) {
+	// Both the schoolbook and the FFT paths agree with evaluation
+	for _, n := range []int{3, 100} {
+		a, b := randPoly(t, n), randPoly(t, n+7)
+		c := Mul(a, b)
+		require.Equal(t, mulSchoolbook(a, b), c)
+
+		var x fr.Element
+		x.SetRandom()
+		ax, bx, cx := Evaluate(a, x), Evaluate(b, x), Evaluate(c, x)
+		ax.Mul(&ax, &bx)
+		require.True(t, ax.Equal(&cx))
+	}
+}
+
+func TestDivide(t *testing.T) {
+	p := randPoly(t, 20)
+	var z fr.Element
+	z.SetRandom()
+
+	q, r := DivideByLinear(p, z)
+	pz := Evaluate(p, z)
+	require.True(t, r.Equal(&pz))
+	var negZ fr.Element
+	negZ.Neg(&z)
+	require.Equal(t, p, Add(Mul(q, []fr.Element{negZ, fr.One()}), []fr.Element{r}))
+
+	// X^8 - 1
+	vanishing := make([]fr.Element, 9)
+	vanishing[0].SetOne()
+	vanishing[0].Neg(&vanishing[0])
+	vanishing[8].SetOne()
+	q, rem := DivideByDomainVanishing(p, 8)
+	qLong, remLong, err := Div(p, vanishing)
+	require.NoError(t, err)
+	require.Equal(t, qLong, q)
+	require.Equal(t, remLong, rem)
+	require.Equal(t, p, Add(Mul(q, vanishing), rem))
+
+	_, _, err = Div(p, []fr.Element{{}})
+	require.ErrorIs(t, err, ErrDivisionByZero)
+}
+
+func TestInterpolate(t *testing.T) {
+	points, values := randPoly(t, 10), randPoly(t, 10)
+	p, err := Interpolate(points, values)
+	require.NoError(t, err)
+	require.Equal(t, 9, Degree(p))
+	for i := range points {
+		v := Evaluate(p, points[i])
+		require.True(t, v.Equal(&values[i]))
+	}
+
+	vanishing := Vanishing(points)
+	for i := range points {
+		v := Evaluate(vanishing, points[i])
+		require.True(t, v.IsZero())
+
+		basis, err := LagrangeBasis(points, i)
+		require.NoError(t, err)
+		for j := range points {
+			v := Evaluate(basis, points[j])
+			require.Equal(t, i == j, v.IsOne())
+		}
+	}
+
+	points[3] = points[5]
+	_, err = Interpolate(points, values)
+	require.ErrorIs(t, err, ErrDuplicatePoints)
+	_, err = Interpolate(points[:2], values)
+	require.ErrorIs(t, err, ErrMismatchedSizes)
+}
@@ -0,0 +1,155 @@
+package fastcommit
+
+import (
+	"errors"
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	crateKzg "github/yyjia/fastcommit/crateKzg/kzg"
+	"github/yyjia/fastcommit/crateKzg/poly"
+)
+
+var (
+	ErrDuplicateKey = errors.New("duplicate key")
+	ErrInvalidKVs   = errors.New("number of keys is not the same as the number of values")
+)
+
+// KeyValueCommit commits to values at points equal to their keys, rather than at the roots of unity.
+//
+// The committed polynomial is the interpolation of the values at the keys, so any field element
+// can be a key. Building and updating it costs O(n²) in the number of keys, this mode suits small
+// and fixed key sets, such as configuration registries.
+type KeyValueCommit struct {
+	commit bls12381.G1Affine
+	keys   map[fr.Element]int
+	points []fr.Element
+	values []fr.Element
+	// coeffs is the interpolation polynomial in monomial form
+	coeffs []fr.Element
+	ctx    *KZGContext
+}
+
+// NewKeyValueCommit commits to values[i] at keys[i] with DefaultKZGContext. At most POLY_SIZE keys are supported.
+func NewKeyValueCommit(keys, values []fr.Element) (*KeyValueCommit, error) {
+	return DefaultKZGContext().NewKeyValueCommit(keys, values)
+}
+
+// NewKeyValueCommit commits to values[i] at keys[i] with the setup of c.
+func (c *KZGContext) NewKeyValueCommit(keys, values []fr.Element) (*KeyValueCommit, error) {
+	if len(keys) != len(values) {
+		return nil, ErrInvalidKVs
+	}
+	if len(keys) > POLY_SIZE {
+		return nil, ErrFullSize
+	}
+
+	keyInd := make(map[fr.Element]int, len(keys))
+	for i := range keys {
+		if _, ok := keyInd[keys[i]]; ok {
+			return nil, ErrDuplicateKey
+		}
+		keyInd[keys[i]] = i
+	}
+
+	coeffs, err := poly.Interpolate(keys, values)
+	if nil != err {
+		return nil, err
+	}
+	commit, err := c.commitMonomial(coeffs)
+	if nil != err {
+		return nil, err
+	}
+
+	s := &KeyValueCommit{
+		commit: commit,
+		keys:   keyInd,
+		points: make([]fr.Element, len(keys)),
+		values: make([]fr.Element, len(values)),
+		coeffs: coeffs,
+		ctx:    c,
+	}
+	copy(s.points, keys)
+	copy(s.values, values)
+	return s, nil
+}
+
+// commitMonomial commits to a polynomial in monomial form with the monomial points of the setup.
+func (c *KZGContext) commitMonomial(coeffs []fr.Element) (bls12381.G1Affine, error) {
+	var commit bls12381.G1Affine
+	if len(coeffs) == 0 {
+		return commit, nil
+	}
+	if len(coeffs) > POLY_SIZE {
+		return commit, ErrFullSize
+	}
+	_, err := commit.MultiExp(c.monomialG1()[:len(coeffs)], coeffs, ecc.MultiExpConfig{})
+	return commit, err
+}
+
+// Commitment returns the commitment to the key-value pairs.
+func (s *KeyValueCommit) Commitment() bls12381.G1Affine {
+	return s.commit
+}
+
+// Get returns the value committed at key.
+func (s *KeyValueCommit) Get(key fr.Element) (fr.Element, error) {
+	i, ok := s.keys[key]
+	if !ok {
+		return fr.Element{}, ErrMissKey
+	}
+	return s.values[i], nil
+}
+
+// Update sets the value at an existing key. The key set is fixed at creation.
+//
+// The polynomial and the commitment move by delta times the lagrange basis polynomial of the key.
+func (s *KeyValueCommit) Update(key, v fr.Element) error {
+	i, ok := s.keys[key]
+	if !ok {
+		return ErrMissKey
+	}
+
+	var delta fr.Element
+	delta.Sub(&v, &s.values[i])
+	if delta.IsZero() {
+		return nil
+	}
+
+	basis, err := poly.LagrangeBasis(s.points, i)
+	if nil != err {
+		return err
+	}
+	basis = poly.Scale(basis, delta)
+	c, err := s.ctx.commitMonomial(basis)
+	if nil != err {
+		return err
+	}
+
+	s.coeffs = poly.Add(s.coeffs, basis)
+	s.commit.Add(&s.commit, &c)
+	s.values[i] = v
+	return nil
+}
+
+// ProofForKey returns the opening proof of the value at key, a commitment to (f(X) - f(key)) / (X - key).
+func (s *KeyValueCommit) ProofForKey(key fr.Element) (bls12381.G1Affine, error) {
+	if _, ok := s.keys[key]; !ok {
+		return bls12381.G1Affine{}, ErrMissKey
+	}
+	quotient, _ := poly.DivideByLinear(s.coeffs, key)
+	return s.ctx.commitMonomial(quotient)
+}
+
+// VerifyForKey verifies a proof made by ProofForKey that the pairs committed in commit hold value at key.
+func VerifyForKey(commit bls12381.G1Affine, key, value fr.Element, proof bls12381.G1Affine) error {
+	return DefaultKZGContext().VerifyForKey(commit, key, value, proof)
+}
+
+// VerifyForKey is VerifyForKey against the setup of c.
+func (c *KZGContext) VerifyForKey(commit bls12381.G1Affine, key, value fr.Element, proof bls12381.G1Affine) error {
+	return c.getPreparedKey().Verify(&commit, &crateKzg.OpeningProof{
+		QuotientCommitment: proof,
+		InputPoint:         key,
+		ClaimedValue:       value,
+	})
+}
@@ -0,0 +1,46 @@
+package fastcommit
+
+import (
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestKeyValueCommit(t *testing.T) {
+	keys := []fr.Element{fr.NewElement(1000), fr.NewElement(7), fr.NewElement(123456789)}
+	values := []fr.Element{fr.NewElement(1), fr.NewElement(2), fr.NewElement(3)}
+	s, err := NewKeyValueCommit(keys, values)
+	assert.Equal(t, nil, err)
+
+	for i := range keys {
+		v, err := s.Get(keys[i])
+		assert.Equal(t, nil, err)
+		assert.Equal(t, values[i], v)
+
+		proof, err := s.ProofForKey(keys[i])
+		assert.Equal(t, nil, err)
+		assert.Equal(t, nil, VerifyForKey(s.Commitment(), keys[i], values[i], proof))
+		assert.NotEqual(t, nil, VerifyForKey(s.Commitment(), keys[i], fr.NewElement(9), proof))
+	}
+
+	// an update moves the commitment to the one of the new pairs
+	err = s.Update(keys[1], fr.NewElement(42))
+	assert.Equal(t, nil, err)
+	values[1] = fr.NewElement(42)
+	fresh, err := NewKeyValueCommit(keys, values)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, fresh.Commitment(), s.Commitment())
+
+	proof, err := s.ProofForKey(keys[1])
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nil, VerifyForKey(s.Commitment(), keys[1], values[1], proof))
+
+	_, err = s.ProofForKey(fr.NewElement(8))
+	assert.Equal(t, ErrMissKey, err)
+	assert.Equal(t, ErrMissKey, s.Update(fr.NewElement(8), fr.NewElement(1)))
+
+	_, err = NewKeyValueCommit([]fr.Element{keys[0], keys[0]}, values[:2])
+	assert.Equal(t, ErrDuplicateKey, err)
+	_, err = NewKeyValueCommit(keys, values[:2])
+	assert.Equal(t, ErrInvalidKVs, err)
+}
@@ -0,0 +1,14 @@
+//go:build !unix
+
+package fastcommit
+
+import "os"
+
+// mapFile reads a whole file, on platforms without mmap.
+func mapFile(path string) ([]byte, func() error, error) {
+	data, err := os.ReadFile(path)
+	if nil != err {
+		return nil, nil, err
+	}
+	return data, func() error { return nil }, nil
+}
@@ -67,12 +67,11 @@ func prepareTestData(t *testing.T) {
 			idx = uint32(len(accounts))
 			accounts[address] = idx
 		}
-		//fmt.Println("address", address, HashToBLSField(v[20:]))
-		Updates(int(idx), HashToBLSField(v[20:]))
+		//fmt.Println("address", address, hashToBLSField(v[20:]))
+		Updates(int(idx), hashToBLSField(v[20:]))
 		j++
 	}
-	Wg.Wait()
-	PrintArrCommit(Branchs)
+	fmt.Printf("Committed %d accounts in %d branches\r\n", len(accounts), len(DefaultKZGContext().branchs))
 	UpdatesRoot()
 }
 
@@ -86,35 +85,22 @@ func Test_MergeProof(t *testing.T) {
 	blob := k / POLY_SIZE
 	ind := k % POLY_SIZE
 
-	v := Branchs[blob].values[ind]
+	v := DefaultKZGContext().branchs[blob].values[ind]
 
 	instance := &Material{k: k, v: v}
 
 	// 路径上的参数
 	np := instance.parseParams()
 	l1 := np.ps[0]
-	b1 := l1.c.Bytes()
 
 	l2 := np.ps[1]
-	assert.Equal(t, l2.v, HashToBLSField(b1[:]))
+	assert.Equal(t, l2.v, CommitmentToField(0, &l1.c))
 
-	b2 := l2.c.Bytes()
 	l3 := np.ps[2]
-	assert.Equal(t, l3.v, HashToBLSField(b2[:]))
+	assert.Equal(t, l3.v, CommitmentToField(1, &l2.c))
 
-	// g(x) 承诺
-	D := instance.CompressCommit(np)
-
-	var rt [32]byte
-	copy(rt[:], np.r.Bytes())
-	// 挑战点
-	input := instance.challengePoint(D.Bytes(), rt)
-
-	// 值
-	output := instance.G2point(np, input)
-
-	// 计算 proof
-	proof, err := instance.proof(np, input, output)
+	// g(x) 承诺 D 与 proof
+	D, proof, err := instance.Prove(np)
 	assert.Equal(t, nil, err)
 
 	// 验证
@@ -0,0 +1,110 @@
+package fastcommit
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+const (
+	// VersionedHashVersionKZG is the version byte of the versioned hash of a KZG commitment.
+	VersionedHashVersionKZG = 0x01
+	// PointEvaluationInputSize is the size of the input of the point-evaluation precompile:
+	// versioned hash, z, y, commitment and proof.
+	PointEvaluationInputSize = 32 + 2*BytesPerFieldElement + 2*BytesPerG1
+	// PointEvaluationOutputSize is the size of the output of the point-evaluation precompile:
+	// the number of field elements per blob and the modulus of the scalar field.
+	PointEvaluationOutputSize = 2 * BytesPerFieldElement
+)
+
+var (
+	ErrInvalidPrecompileInput = errors.New("point-evaluation input must be 192 bytes")
+	ErrVersionedHashMismatch  = errors.New("versioned hash does not match the commitment")
+)
+
+// VersionedHash is the hash under which a commitment is referenced on chain.
+type VersionedHash [32]byte
+
+// KZGToVersionedHash returns the versioned hash of commitment, as [kzg_to_versioned_hash]:
+// the version byte followed by the last 31 bytes of the sha256 of the commitment.
+//
+// [kzg_to_versioned_hash]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/beacon-chain.md#kzg_to_versioned_hash
+func KZGToVersionedHash(commitment KZGCommitment) VersionedHash {
+	h := VersionedHash(sha256.Sum256(commitment[:]))
+	h[0] = VersionedHashVersionKZG
+	return h
+}
+
+// VersionedHash returns the versioned hash of the commitment of the branch.
+func (s *ValueCommit) VersionedHash() VersionedHash {
+	return KZGToVersionedHash(s.commit.Bytes())
+}
+
+// PointEvaluationInput returns the input of the point-evaluation precompile that proves the value
+// of the branch at z, with the proof of ProofForVal. For the slot i, z is the i'th root of the domain.
+//
+// The precompile checks the proof against the mainnet setup, the branch must be committed with it.
+func (s *ValueCommit) PointEvaluationInput(z fr.Element) ([PointEvaluationInputSize]byte, error) {
+	var input [PointEvaluationInputSize]byte
+
+	proof, err := s.ProofForVal(z)
+	if nil != err {
+		return input, err
+	}
+	y, err := s.context().domain.EvaluateLagrangePolynomial(s.values, z)
+	if nil != err {
+		return input, err
+	}
+
+	h := s.VersionedHash()
+	zb, yb := z.Bytes(), y.Bytes()
+	c, p := s.commit.Bytes(), proof.Bytes()
+	off := copy(input[:], h[:])
+	off += copy(input[off:], zb[:])
+	off += copy(input[off:], yb[:])
+	off += copy(input[off:], c[:])
+	copy(input[off:], p[:])
+	return input, nil
+}
+
+// VerifyPointEvaluation checks an input of the point-evaluation precompile against the setup of c,
+// with the semantics of [point_evaluation_precompile]: the input is 192 bytes, the versioned hash is
+// the one of the commitment, and the proof opens the commitment to y at z. It returns the output of
+// the precompile.
+//
+// [point_evaluation_precompile]: https://eips.ethereum.org/EIPS/eip-4844#point-evaluation-precompile
+func (c *KZGContext) VerifyPointEvaluation(input []byte) ([PointEvaluationOutputSize]byte, error) {
+	var output [PointEvaluationOutputSize]byte
+	if len(input) != PointEvaluationInputSize {
+		return output, ErrInvalidPrecompileInput
+	}
+
+	var (
+		h          VersionedHash
+		z, y       Scalar
+		commitment KZGCommitment
+		proof      KZGProof
+	)
+	off := copy(h[:], input)
+	off += copy(z[:], input[off:])
+	off += copy(y[:], input[off:])
+	off += copy(commitment[:], input[off:])
+	copy(proof[:], input[off:])
+
+	if KZGToVersionedHash(commitment) != h {
+		return output, ErrVersionedHashMismatch
+	}
+	if err := c.VerifyKZGProof(commitment, z, y, proof); nil != err {
+		return output, err
+	}
+
+	binary.BigEndian.PutUint64(output[BytesPerFieldElement-8:BytesPerFieldElement], ScalarSize)
+	fr.Modulus().FillBytes(output[BytesPerFieldElement:])
+	return output, nil
+}
+
+// VerifyPointEvaluation is VerifyPointEvaluation of EIP4844Context, the precompile as on mainnet.
+func VerifyPointEvaluation(input []byte) ([PointEvaluationOutputSize]byte, error) {
+	return EIP4844Context().VerifyPointEvaluation(input)
+}
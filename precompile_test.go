@@ -0,0 +1,71 @@
+package fastcommit
+
+import (
+	"encoding/hex"
+	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+func TestPointEvaluationInput(t *testing.T) {
+	// the output of the precompile: FIELD_ELEMENTS_PER_BLOB and BLS_MODULUS
+	const expectedOutput = "0000000000000000000000000000000000000000000000000000000000001000" +
+		"73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001"
+
+	fc := NewContext(dataCase)
+	input, err := fc.PointEvaluationInput(DefaultKZGContext().domain.Roots[3])
+	assert.Equal(t, nil, err)
+	assert.Equal(t, byte(VersionedHashVersionKZG), input[0])
+	y := fc.values[3].Bytes()
+	assert.Equal(t, y[:], input[64:96])
+
+	output, err := VerifyPointEvaluation(input[:])
+	assert.Equal(t, nil, err)
+	assert.Equal(t, expectedOutput, hex.EncodeToString(output[:]))
+
+	// any point may be opened
+	input, err = fc.PointEvaluationInput(hashToBLSField([]byte("z")))
+	assert.Equal(t, nil, err)
+	_, err = VerifyPointEvaluation(input[:])
+	assert.Equal(t, nil, err)
+
+	_, err = VerifyPointEvaluation(input[:191])
+	assert.Equal(t, ErrInvalidPrecompileInput, err)
+	tampered := input
+	tampered[5] ^= 1
+	_, err = VerifyPointEvaluation(tampered[:])
+	assert.Equal(t, ErrVersionedHashMismatch, err)
+	tampered = input
+	tampered[95] ^= 1
+	_, err = VerifyPointEvaluation(tampered[:])
+	assert.NotEqual(t, nil, err)
+}
+
+func TestPointEvaluationAgainstGoKZG(t *testing.T) {
+	// go-kzg-4844 embeds the insecure setup of secret 1337
+	dev, err := NewInsecureDevSetup(ScalarSize, big.NewInt(1337))
+	assert.Equal(t, nil, err)
+	ctx, err := NewKZGContext(&dev.Setup)
+	assert.Equal(t, nil, err)
+	gctx, err := gokzg4844.NewContext4096Insecure1337()
+	assert.Equal(t, nil, err)
+
+	fc := ctx.NewValueCommit(dataCase)
+	input, err := fc.PointEvaluationInput(ctx.domain.Roots[3])
+	assert.Equal(t, nil, err)
+	_, err = ctx.VerifyPointEvaluation(input[:])
+	assert.Equal(t, nil, err)
+
+	var (
+		z, y       gokzg4844.Scalar
+		commitment gokzg4844.KZGCommitment
+		proof      gokzg4844.KZGProof
+	)
+	copy(z[:], input[32:64])
+	copy(y[:], input[64:96])
+	copy(commitment[:], input[96:144])
+	copy(proof[:], input[144:])
+	assert.Equal(t, nil, gctx.VerifyKZGProof(commitment, z, y, proof))
+	assert.Equal(t, fc.VersionedHash(), KZGToVersionedHash(KZGCommitment(commitment)))
+}
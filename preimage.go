@@ -0,0 +1,96 @@
+package fastcommit
+
+import (
+	"errors"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+var (
+	ErrMissPreimage  = errors.New("miss preimage")
+	ErrStalePreimage = errors.New("preimage does not match the slot value")
+)
+
+// PreimageStore keeps the original bytes of the slots whose value is their hash.
+type PreimageStore struct {
+	preimages map[int][]byte
+}
+
+func NewPreimageStore() *PreimageStore {
+	return &PreimageStore{preimages: make(map[int][]byte)}
+}
+
+// Put records a copy of data as the preimage of slot.
+func (s *PreimageStore) Put(slot int, data []byte) {
+	s.preimages[slot] = append([]byte(nil), data...)
+}
+
+// Get returns a copy of the preimage of slot.
+func (s *PreimageStore) Get(slot int) ([]byte, bool) {
+	data, ok := s.preimages[slot]
+	if !ok {
+		return nil, false
+	}
+	return append([]byte(nil), data...), true
+}
+
+// Delete forgets the preimage of slot.
+func (s *PreimageStore) Delete(slot int) {
+	delete(s.preimages, slot)
+}
+
+// UpdatePreimage sets slot index of the branches of DefaultKZGContext to the hash of data and records data as its preimage.
+func UpdatePreimage(index int, data []byte) error {
+	return DefaultKZGContext().UpdatePreimage(index, data)
+}
+
+// UpdatePreimage sets slot index to the hash of data and records data as its preimage.
+func (c *KZGContext) UpdatePreimage(index int, data []byte) error {
+	if err := c.Updates(index, hashToBLSField(data)); nil != err {
+		return err
+	}
+	c.preimages.Put(index, data)
+	return nil
+}
+
+// PreimageProof opens a slot and carries the bytes its value was hashed from.
+type PreimageProof struct {
+	Data  []byte
+	Proof bls12381.G1Affine
+}
+
+// ProofForPreimage is ProofForPreimage of DefaultKZGContext.
+func ProofForPreimage(index int) (*PreimageProof, error) {
+	return DefaultKZGContext().ProofForPreimage(index)
+}
+
+// ProofForPreimage opens slot index along with its preimage.
+func (c *KZGContext) ProofForPreimage(index int) (*PreimageProof, error) {
+	data, ok := c.preimages.Get(index)
+	if !ok {
+		return nil, ErrMissPreimage
+	}
+
+	vals, proofs, err := c.proofForSlots([]int{index})
+	if nil != err {
+		return nil, err
+	}
+	// the slot may have been overwritten without going through UpdatePreimage
+	h := hashToBLSField(data)
+	if !h.Equal(&vals[0]) {
+		return nil, ErrStalePreimage
+	}
+	return &PreimageProof{Data: data, Proof: proofs[0]}, nil
+}
+
+// Verify is VerifyPreimageProof of DefaultKZGContext.
+func (p *PreimageProof) Verify(index int, commits map[int]bls12381.G1Affine) error {
+	return DefaultKZGContext().VerifyPreimageProof(p, index, commits)
+}
+
+// VerifyPreimageProof recomputes the slot value from p.Data and checks it against the commitments
+// of the branches, keyed by branch number.
+func (c *KZGContext) VerifyPreimageProof(p *PreimageProof, index int, commits map[int]bls12381.G1Affine) error {
+	h := hashToBLSField(p.Data)
+	return c.verifySlots([]int{index}, []fr.Element{h}, []bls12381.G1Affine{p.Proof}, commits)
+}
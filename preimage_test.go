@@ -0,0 +1,61 @@
+package fastcommit
+
+import (
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+func TestPreimageProof_Verify(t *testing.T) {
+	index := 4*POLY_SIZE + 7
+	account := []byte("account rlp")
+	err := UpdatePreimage(index, account)
+	assert.Equal(t, nil, err)
+
+	proof, err := ProofForPreimage(index)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, account, proof.Data)
+
+	commits := map[int]bls12381.G1Affine{4: DefaultKZGContext().branchs[4].commit}
+	err = proof.Verify(index, commits)
+	assert.Equal(t, nil, err)
+
+	// the proof does not share its bytes with the store, nor the store with the caller
+	proof.Data[0] = 'A'
+	account[1] = 'C'
+	again, err := ProofForPreimage(index)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []byte("account rlp"), again.Data)
+
+	proof.Data = []byte("another account")
+	err = proof.Verify(index, commits)
+	assert.NotEqual(t, nil, err)
+
+	// overwriting the slot directly leaves a stale preimage behind
+	err = Updates(index, fr.NewElement(1))
+	assert.Equal(t, nil, err)
+	_, err = ProofForPreimage(index)
+	assert.Equal(t, ErrStalePreimage, err)
+
+	_, err = ProofForPreimage(index + 1)
+	assert.Equal(t, ErrMissPreimage, err)
+}
+
+func TestPreimageProofWithContext(t *testing.T) {
+	dev, err := NewInsecureDevSetup(ScalarSize, big.NewInt(1337))
+	assert.Equal(t, nil, err)
+	ctx, err := NewKZGContext(&dev.Setup)
+	assert.Equal(t, nil, err)
+
+	// the branches of a context are its own, and proofs verify against its setup only
+	index := 2*POLY_SIZE + 1
+	assert.Equal(t, nil, ctx.UpdatePreimage(index, []byte("account rlp")))
+	proof, err := ctx.ProofForPreimage(index)
+	assert.Equal(t, nil, err)
+
+	commits := map[int]bls12381.G1Affine{2: ctx.branchs[2].commit}
+	assert.Equal(t, nil, ctx.VerifyPreimageProof(proof, index, commits))
+	assert.NotEqual(t, nil, proof.Verify(index, commits))
+}
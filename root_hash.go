@@ -0,0 +1,87 @@
+package fastcommit
+
+import (
+	"errors"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// TreeDepth is the number of levels of branches between a leaf and the root.
+const TreeDepth = 3
+
+// DomSepRootHash is a Domain Separator for the root hash of the tree.
+const DomSepRootHash = "FASTCOMMIT_ROOT_V1_"
+
+var (
+	ErrRootMismatch = errors.New("root hash does not match the proof")
+	ErrPathMismatch = errors.New("proof path does not match the key")
+)
+
+// SRSID returns a digest of the trusted setup of DefaultKZGContext.
+func SRSID() [32]byte {
+	return DefaultKZGContext().SRSID()
+}
+
+// RootHash returns the RootHash of the tree of DefaultKZGContext.
+func RootHash() [32]byte {
+	return DefaultKZGContext().RootHash()
+}
+
+// RootHash returns the canonical 32-byte digest of the tree of c, fit to be put in a block header.
+// The levels above the leaves are those of the last call to UpdatesRoot.
+func (c *KZGContext) RootHash() [32]byte {
+	var root bls12381.G1Affine
+	if len(c.branchs2) > 0 {
+		root = *c.branchs2[0].C()
+	}
+	return rootHash(c, root)
+}
+
+// rootHash is sha256(DomSepRootHash || width || depth || SRSID || mapper || root).
+func rootHash(ctx *KZGContext, root bls12381.G1Affine) [32]byte {
+	rb := root.Bytes()
+	srsID := ctx.SRSID()
+	mID := ctx.mapperID()
+	return hash256(
+		[]byte(DomSepRootHash),
+		u64ToByteArray16(POLY_SIZE),
+		u64ToByteArray16(TreeDepth),
+		srsID[:],
+		mID[:],
+		rb[:],
+	)
+}
+
+// VerifyRoot verifies the proof like Verify, and in addition checks that the path
+// of np leads from the key of s up to the tree whose RootHash is root.
+func (s *Material) VerifyRoot(root [32]byte, np NeedParams, D bls12381.G1Affine, proof bls12381.G1Affine) error {
+	if err := s.checkPath(np); nil != err {
+		return err
+	}
+	if rootHash(s.context(), np.ps[TreeDepth-1].c) != root {
+		return ErrRootMismatch
+	}
+	return s.Verify(np, D, proof)
+}
+
+// checkPath checks that np opens the slots of the key of s at every level,
+// and that each level opens to the commitment of the level below.
+func (s *Material) checkPath(np NeedParams) error {
+	if len(np.ps) != TreeDepth || !np.ps[0].v.Equal(&s.v) {
+		return ErrPathMismatch
+	}
+	b := s.k
+	for level := 0; level < TreeDepth; level++ {
+		w := s.context().domain.Roots[b%POLY_SIZE]
+		if !np.ps[level].k.Equal(&w) {
+			return ErrPathMismatch
+		}
+		b = b / POLY_SIZE
+		if level == 0 {
+			continue
+		}
+		if v := s.context().CommitmentToField(level-1, &np.ps[level-1].c); !np.ps[level].v.Equal(&v) {
+			return ErrPathMismatch
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package fastcommit
+
+import (
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRootHash(t *testing.T) {
+	fc := NewContext(dataCase)
+	h := rootHash(DefaultKZGContext(), fc.commit)
+	assert.Equal(t, h, rootHash(DefaultKZGContext(), fc.commit))
+
+	err := fc.Update(5, fr.NewElement(5))
+	assert.Equal(t, nil, err)
+	assert.NotEqual(t, h, rootHash(DefaultKZGContext(), fc.commit))
+}
+
+func TestMaterial_checkPath(t *testing.T) {
+	var k uint32 = 3*POLY_SIZE + 9
+	_, _, g1, _ := bls12381.Generators()
+	var c1, c2 bls12381.G1Affine
+	c1.Double(&g1)
+	c2.Add(&c1, &g1)
+
+	s := &Material{k: k, v: fr.NewElement(42)}
+	np := NeedParams{ps: []params{
+		{k: DefaultKZGContext().domain.Roots[9], v: s.v, c: g1},
+		{k: DefaultKZGContext().domain.Roots[3], v: CommitmentToField(0, &g1), c: c1},
+		{k: DefaultKZGContext().domain.Roots[0], v: CommitmentToField(1, &c1), c: c2},
+	}}
+	assert.Equal(t, nil, s.checkPath(np))
+
+	// a parent slot that does not hold the child commitment
+	np.ps[2].v = fr.NewElement(1)
+	assert.Equal(t, ErrPathMismatch, s.checkPath(np))
+	np.ps[2].v = CommitmentToField(1, &c1)
+
+	// an opening at the wrong position
+	np.ps[1].k = DefaultKZGContext().domain.Roots[4]
+	assert.Equal(t, ErrPathMismatch, s.checkPath(np))
+	np.ps[1].k = DefaultKZGContext().domain.Roots[3]
+
+	err := s.VerifyRoot(rootHash(DefaultKZGContext(), g1), np, g1, g1)
+	assert.Equal(t, ErrRootMismatch, err)
+}
+
+func TestMaterial_VerifyRoot(t *testing.T) {
+	ctx := buildTestTree()
+	p := treeProofFor(t, ctx, 5)
+	root := ctx.RootHash()
+	assert.Equal(t, nil, p.Material.VerifyRoot(root, p.Params, p.D, p.Proof))
+
+	// a path through another leaf value
+	np := NeedParams{ps: append([]params{}, p.Params.ps...)}
+	np.ps[0].v = fr.NewElement(1)
+	assert.Equal(t, ErrPathMismatch, p.Material.VerifyRoot(root, np, p.D, p.Proof))
+
+	// a path that does not end at the root
+	np = NeedParams{ps: append([]params{}, p.Params.ps...)}
+	np.ps[2].c = np.ps[1].c
+	assert.Equal(t, ErrRootMismatch, p.Material.VerifyRoot(root, np, p.D, p.Proof))
+
+	// a root over another tree
+	assert.Equal(t, ErrRootMismatch, p.Material.VerifyRoot(rootHash(ctx, p.Params.ps[1].c), p.Params, p.D, p.Proof))
+
+	// the right path with a forged proof
+	_, _, g1, _ := bls12381.Generators()
+	forged := p.Proof
+	forged.Add(&forged, &g1)
+	assert.NotEqual(t, nil, p.Material.VerifyRoot(root, p.Params, p.D, forged))
+}
@@ -0,0 +1,214 @@
+package fastcommit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/kzg"
+	"io"
+	"math/bits"
+	"os"
+)
+
+// setupBinaryMagic starts a setup in the compact binary format written by Setup.WriteBinary.
+const setupBinaryMagic = "FCSETUP1"
+
+var (
+	ErrInvalidSetup = errors.New("invalid trusted setup")
+	ErrSetupSize    = errors.New("trusted setup does not match the width of the tree")
+)
+
+// Setup is a trusted setup, as produced by a powers-of-tau ceremony.
+type Setup struct {
+	// G1 is the generator of G1
+	G1 bls12381.G1Affine
+	// LagrangeG1 are the commitments [L_i(α)]G₁ to the lagrange polynomials of the domain
+	LagrangeG1 []bls12381.G1Affine
+	// G2 are the powers [α^i]G₂, at least [1]G₂ and [α]G₂
+	G2 []bls12381.G2Affine
+}
+
+// jsonSetup accepts both JSON layouts of the EIP-4844 setup: the one of go-kzg-4844 and c-kzg-4844,
+// and the one of the consensus-specs, which has no monomial G1 points.
+type jsonSetup struct {
+	SetupG1         []string `json:"setup_G1,omitempty"`
+	SetupG2         []string `json:"setup_G2,omitempty"`
+	SetupG1Lagrange []string `json:"setup_G1_lagrange,omitempty"`
+
+	G1Monomial []string `json:"g1_monomial,omitempty"`
+	G1Lagrange []string `json:"g1_lagrange,omitempty"`
+	G2Monomial []string `json:"g2_monomial,omitempty"`
+}
+
+// ParseSetupJSON reads a setup in the JSON format of trusted_setup.json.
+//
+// The points are trusted, they are not checked to be in the prime-order subgroups.
+func ParseSetupJSON(r io.Reader) (*Setup, error) {
+	s, _, err := parseSetupJSON(r)
+	return s, err
+}
+
+// parseSetupJSON reads a setup in the JSON format, along with the monomial G1 points
+// when the file holds them.
+func parseSetupJSON(r io.Reader) (*Setup, []string, error) {
+	var js jsonSetup
+	if err := json.NewDecoder(r).Decode(&js); nil != err {
+		return nil, nil, err
+	}
+
+	lagrange, g2, monomial := js.SetupG1Lagrange, js.SetupG2, js.SetupG1
+	if len(lagrange) == 0 {
+		lagrange, g2, monomial = js.G1Lagrange, js.G2Monomial, js.G1Monomial
+	}
+
+	s := &Setup{}
+	// The generator is the degree-0 element of the monomial points, when they are given
+	if len(monomial) > 0 {
+		g1, err := parseG1PointNoSubgroupCheck(monomial[0])
+		if nil != err {
+			return nil, nil, err
+		}
+		s.G1 = g1
+	} else {
+		_, _, s.G1, _ = bls12381.Generators()
+	}
+
+	var err error
+	if s.LagrangeG1, err = parseG1PointsNoSubgroupCheck(lagrange); nil != err {
+		return nil, nil, err
+	}
+	if s.G2, err = parseG2PointsNoSubgroupCheck(g2); nil != err {
+		return nil, nil, err
+	}
+	return s, monomial, s.checkSizes()
+}
+
+// ParseSetupBinary reads a setup in the compact binary format written by WriteBinary.
+//
+// The points are trusted, they are not checked to be in the prime-order subgroups.
+func ParseSetupBinary(r io.Reader) (*Setup, error) {
+	magic := make([]byte, len(setupBinaryMagic))
+	if _, err := io.ReadFull(r, magic); nil != err {
+		return nil, err
+	}
+	if string(magic) != setupBinaryMagic {
+		return nil, ErrInvalidSetup
+	}
+	var sizes [2]uint32
+	if err := binary.Read(r, binary.BigEndian, &sizes); nil != err {
+		return nil, err
+	}
+	// Reject sizes that could not be a setup before allocating for them
+	if sizes[0] > 1<<28 || sizes[1] > 1<<28 {
+		return nil, ErrInvalidSetup
+	}
+
+	s := &Setup{
+		LagrangeG1: make([]bls12381.G1Affine, sizes[0]),
+		G2:         make([]bls12381.G2Affine, sizes[1]),
+	}
+	d := bls12381.NewDecoder(r, bls12381.NoSubgroupChecks())
+	if err := d.Decode(&s.G1); nil != err {
+		return nil, err
+	}
+	for i := range s.LagrangeG1 {
+		if err := d.Decode(&s.LagrangeG1[i]); nil != err {
+			return nil, err
+		}
+	}
+	for i := range s.G2 {
+		if err := d.Decode(&s.G2[i]); nil != err {
+			return nil, err
+		}
+	}
+	return s, s.checkSizes()
+}
+
+// WriteBinary writes the setup in a compact binary format: a magic, the number of
+// lagrange and G2 points, then every point compressed.
+func (s *Setup) WriteBinary(w io.Writer) error {
+	if _, err := io.WriteString(w, setupBinaryMagic); nil != err {
+		return err
+	}
+	sizes := [2]uint32{uint32(len(s.LagrangeG1)), uint32(len(s.G2))}
+	if err := binary.Write(w, binary.BigEndian, sizes); nil != err {
+		return err
+	}
+	e := bls12381.NewEncoder(w)
+	if err := e.Encode(&s.G1); nil != err {
+		return err
+	}
+	for i := range s.LagrangeG1 {
+		if err := e.Encode(&s.LagrangeG1[i]); nil != err {
+			return err
+		}
+	}
+	for i := range s.G2 {
+		if err := e.Encode(&s.G2[i]); nil != err {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseSetup reads a setup in the JSON, the binary or the raw cache format, told apart by the magic.
+func ParseSetup(r io.Reader) (*Setup, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(len(setupBinaryMagic))
+	if nil == err && bytes.Equal(magic, []byte(setupBinaryMagic)) {
+		return ParseSetupBinary(br)
+	}
+	if nil == err && bytes.Equal(magic, []byte(setupCacheMagic)) {
+		data, err := io.ReadAll(br)
+		if nil != err {
+			return nil, err
+		}
+		return ParseSetupCache(data)
+	}
+	return ParseSetupJSON(br)
+}
+
+// LoadSetupFile reads a setup from a file in the JSON, the binary or the raw cache format.
+// A raw cache is memory-mapped, see LoadSetupCache.
+func LoadSetupFile(path string) (*Setup, error) {
+	f, err := os.Open(path)
+	if nil != err {
+		return nil, err
+	}
+	defer f.Close()
+
+	br := bufio.NewReader(f)
+	magic, err := br.Peek(len(setupCacheMagic))
+	if nil == err && bytes.Equal(magic, []byte(setupCacheMagic)) {
+		return LoadSetupCache(path)
+	}
+	return ParseSetup(br)
+}
+
+// checkSizes checks that the setup has a power of two lagrange points and at least two G2 points.
+func (s *Setup) checkSizes() error {
+	if len(s.G2) < 2 {
+		return kzg.ErrMinSRSSize
+	}
+	if bits.OnesCount(uint(len(s.LagrangeG1))) != 1 {
+		return ErrInvalidSetup
+	}
+	return nil
+}
+
+// SetSetup replaces the setup of DefaultKZGContext, which is the embedded trusted_setup.json otherwise.
+//
+// It must be called before any branch is committed with the default context, commitments and proofs
+// made over another setup do not verify against it. Branches and verifiers already holding the previous
+// context keep using it. The slots set by Updates are held by the context, they start empty again.
+func SetSetup(s *Setup, opts ...ContextOption) error {
+	ctx, err := NewKZGContext(s, opts...)
+	if nil != err {
+		return err
+	}
+	defaultCtx.Store(ctx)
+	return nil
+}
@@ -0,0 +1,158 @@
+package fastcommit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"io"
+	"os"
+)
+
+// setupCacheMagic starts a setup in the raw cache format written by Setup.WriteCache.
+const setupCacheMagic = "FCSRSRAW"
+
+const (
+	// setupCacheHeader is the size of the magic and of the two point counts
+	setupCacheHeader = len(setupCacheMagic) + 8
+	// g1RawSize and g2RawSize are the sizes of the uncompressed affine coordinates of a point
+	g1RawSize = 2 * fp.Bytes
+	g2RawSize = 4 * fp.Bytes
+)
+
+var ErrSetupChecksum = errors.New("trusted setup cache checksum mismatch")
+
+// WriteCache writes the setup in the raw cache format: a magic, the number of lagrange and G2 points,
+// the affine coordinates of every point uncompressed, then the sha256 of all the preceding bytes.
+//
+// The cache is about twice the size of the binary format, but loading it needs no square root
+// and no hex decoding. A G2 point is written as X.A1, X.A0, Y.A1, Y.A0, each big-endian.
+func (s *Setup) WriteCache(w io.Writer) error {
+	h := sha256.New()
+	bw := bufio.NewWriter(io.MultiWriter(w, h))
+
+	bw.WriteString(setupCacheMagic)
+	sizes := [2]uint32{uint32(len(s.LagrangeG1)), uint32(len(s.G2))}
+	if err := binary.Write(bw, binary.BigEndian, sizes); nil != err {
+		return err
+	}
+	writeRawG1(bw, &s.G1)
+	for i := range s.LagrangeG1 {
+		writeRawG1(bw, &s.LagrangeG1[i])
+	}
+	for i := range s.G2 {
+		writeRawG2(bw, &s.G2[i])
+	}
+	if err := bw.Flush(); nil != err {
+		return err
+	}
+	_, err := w.Write(h.Sum(nil))
+	return err
+}
+
+// WriteCacheFile writes the setup to a file in the raw cache format.
+func (s *Setup) WriteCacheFile(path string) error {
+	f, err := os.Create(path)
+	if nil != err {
+		return err
+	}
+	if err = s.WriteCache(f); nil != err {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ParseSetupCache reads a setup in the raw cache format written by WriteCache.
+//
+// The checksum is verified and every coordinate must be reduced, but the points are not
+// checked to be on the curve: the cache is meant to be written from a setup already trusted.
+func ParseSetupCache(data []byte) (*Setup, error) {
+	if len(data) < setupCacheHeader+g1RawSize+sha256.Size || string(data[:len(setupCacheMagic)]) != setupCacheMagic {
+		return nil, ErrInvalidSetup
+	}
+	nLagrange := binary.BigEndian.Uint32(data[len(setupCacheMagic):])
+	nG2 := binary.BigEndian.Uint32(data[len(setupCacheMagic)+4:])
+	// Reject sizes that could not be a setup before allocating for them
+	if nLagrange > 1<<28 || nG2 > 1<<28 {
+		return nil, ErrInvalidSetup
+	}
+	body := setupCacheHeader + (1+int(nLagrange))*g1RawSize + int(nG2)*g2RawSize
+	if len(data) != body+sha256.Size {
+		return nil, ErrInvalidSetup
+	}
+	sum := sha256.Sum256(data[:body])
+	if !bytes.Equal(sum[:], data[body:]) {
+		return nil, ErrSetupChecksum
+	}
+
+	s := &Setup{
+		LagrangeG1: make([]bls12381.G1Affine, nLagrange),
+		G2:         make([]bls12381.G2Affine, nG2),
+	}
+	off := setupCacheHeader
+	if err := readRawG1(data[off:], &s.G1); nil != err {
+		return nil, err
+	}
+	off += g1RawSize
+	for i := range s.LagrangeG1 {
+		if err := readRawG1(data[off:], &s.LagrangeG1[i]); nil != err {
+			return nil, err
+		}
+		off += g1RawSize
+	}
+	for i := range s.G2 {
+		if err := readRawG2(data[off:], &s.G2[i]); nil != err {
+			return nil, err
+		}
+		off += g2RawSize
+	}
+	return s, s.checkSizes()
+}
+
+// LoadSetupCache reads a setup in the raw cache format from a file, memory-mapped where the platform allows.
+func LoadSetupCache(path string) (*Setup, error) {
+	data, unmap, err := mapFile(path)
+	if nil != err {
+		return nil, err
+	}
+	defer unmap()
+	return ParseSetupCache(data)
+}
+
+func writeRawG1(w io.Writer, p *bls12381.G1Affine) {
+	for _, e := range []*fp.Element{&p.X, &p.Y} {
+		b := e.Bytes()
+		w.Write(b[:])
+	}
+}
+
+func writeRawG2(w io.Writer, p *bls12381.G2Affine) {
+	for _, e := range []*fp.Element{&p.X.A1, &p.X.A0, &p.Y.A1, &p.Y.A0} {
+		b := e.Bytes()
+		w.Write(b[:])
+	}
+}
+
+func readRawG1(data []byte, p *bls12381.G1Affine) error {
+	return readRawCoordinates(data, &p.X, &p.Y)
+}
+
+func readRawG2(data []byte, p *bls12381.G2Affine) error {
+	return readRawCoordinates(data, &p.X.A1, &p.X.A0, &p.Y.A1, &p.Y.A0)
+}
+
+// readRawCoordinates reads big-endian coordinates, which must be reduced modulo p.
+func readRawCoordinates(data []byte, coordinates ...*fp.Element) error {
+	for i, e := range coordinates {
+		v, err := fp.BigEndian.Element((*[fp.Bytes]byte)(data[i*fp.Bytes : (i+1)*fp.Bytes]))
+		if nil != err {
+			return errors.Join(ErrInvalidSetup, err)
+		}
+		*e = v
+	}
+	return nil
+}
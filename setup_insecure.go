@@ -0,0 +1,108 @@
+package fastcommit
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	crateKzg "github/yyjia/fastcommit/crateKzg/kzg"
+	"io"
+	"math/big"
+	"math/bits"
+	"os"
+)
+
+// insecureDevSetupG2Points is the number of G2 powers of a dev setup, as many as the EIP-4844 setup has.
+const insecureDevSetupG2Points = 65
+
+// InsecureDevSetup is a trusted setup generated from a known secret.
+//
+// INSECURE: whoever knows the secret can open a commitment to any value. It is meant for local
+// devnets and tests, which can then run with small widths and reproducible setups.
+type InsecureDevSetup struct {
+	Setup
+	// MonomialG1 are the powers [α^i]G₁, of which LagrangeG1 is the inverse FFT
+	MonomialG1 []bls12381.G1Affine
+}
+
+// NewInsecureDevSetup generates a setup of width points from secret. The width must be a power of two, at least 2.
+//
+// INSECURE: never use the result to commit to anything of value, see InsecureDevSetup.
+func NewInsecureDevSetup(width uint64, secret *big.Int) (*InsecureDevSetup, error) {
+	if width < 2 || bits.OnesCount64(width) != 1 {
+		return nil, fmt.Errorf("%w: width %d is not a power of two", ErrInvalidSetup, width)
+	}
+	monomial, lagrange, err := crateKzg.NewInsecureDevSRS(crateKzg.NewDomain(width), secret)
+	if nil != err {
+		return nil, err
+	}
+
+	var alpha fr.Element
+	alpha.SetBigInt(secret)
+	powers := make([]fr.Element, insecureDevSetupG2Points-1)
+	powers[0] = alpha
+	for i := 1; i < len(powers); i++ {
+		powers[i].Mul(&powers[i-1], &alpha)
+	}
+	g2 := make([]bls12381.G2Affine, insecureDevSetupG2Points)
+	g2[0] = monomial.OpeningKey.GenG2
+	copy(g2[1:], bls12381.BatchScalarMultiplicationG2(&g2[0], powers))
+
+	return &InsecureDevSetup{
+		Setup: Setup{
+			G1:         monomial.OpeningKey.GenG1,
+			LagrangeG1: lagrange.CommitKey.G1,
+			G2:         g2,
+		},
+		MonomialG1: monomial.CommitKey.G1,
+	}, nil
+}
+
+// WriteJSON writes the setup in the format of trusted_setup.json, with the monomial G1 points
+// under g1_monomial, so that ParseSetupJSONValidated checks them against the lagrange points.
+func (s *InsecureDevSetup) WriteJSON(w io.Writer) error {
+	return writeSetupJSON(w, &s.Setup, s.MonomialG1)
+}
+
+// WriteJSONFile writes the setup to a file in the format of trusted_setup.json.
+func (s *InsecureDevSetup) WriteJSONFile(path string) error {
+	f, err := os.Create(path)
+	if nil != err {
+		return err
+	}
+	if err = s.WriteJSON(f); nil != err {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// WriteJSON writes the setup in the format of trusted_setup.json, the layout of the consensus-specs.
+func (s *Setup) WriteJSON(w io.Writer) error {
+	return writeSetupJSON(w, s, nil)
+}
+
+func writeSetupJSON(w io.Writer, s *Setup, monomial []bls12381.G1Affine) error {
+	js := jsonSetup{
+		G1Monomial: make([]string, len(monomial)),
+		G1Lagrange: make([]string, len(s.LagrangeG1)),
+		G2Monomial: make([]string, len(s.G2)),
+	}
+	for i := range monomial {
+		b := monomial[i].Bytes()
+		js.G1Monomial[i] = "0x" + hex.EncodeToString(b[:])
+	}
+	for i := range s.LagrangeG1 {
+		b := s.LagrangeG1[i].Bytes()
+		js.G1Lagrange[i] = "0x" + hex.EncodeToString(b[:])
+	}
+	for i := range s.G2 {
+		b := s.G2[i].Bytes()
+		js.G2Monomial[i] = "0x" + hex.EncodeToString(b[:])
+	}
+
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(js)
+}
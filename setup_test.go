@@ -0,0 +1,150 @@
+package fastcommit
+
+import (
+	"bytes"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/stretchr/testify/assert"
+	crateKzg "github/yyjia/fastcommit/crateKzg/kzg"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseSetup(t *testing.T) {
+	config, err := content.ReadFile("trusted_setup.json")
+	assert.Equal(t, nil, err)
+	fromJSON, err := ParseSetup(bytes.NewReader(config))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, ScalarSize, len(fromJSON.LagrangeG1))
+	assert.Equal(t, DefaultKZGContext().srs.Pk.G1, fromJSON.LagrangeG1)
+	assert.Equal(t, DefaultKZGContext().srs.Vk.G1, fromJSON.G1)
+
+	// the binary format round trips
+	var buf bytes.Buffer
+	assert.Equal(t, nil, fromJSON.WriteBinary(&buf))
+	assert.Less(t, buf.Len(), len(config))
+
+	path := filepath.Join(t.TempDir(), "setup.bin")
+	assert.Equal(t, nil, os.WriteFile(path, buf.Bytes(), 0o600))
+	fromFile, err := LoadSetupFile(path)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, fromJSON, fromFile)
+
+	// installing the same setup keeps commitments unchanged
+	before := NewContext(dataCase).commit
+	assert.Equal(t, nil, SetSetup(fromFile))
+	assert.Equal(t, before, NewContext(dataCase).commit)
+
+	small := &Setup{G1: fromFile.G1, LagrangeG1: fromFile.LagrangeG1[:16], G2: fromFile.G2}
+	assert.Equal(t, ErrSetupSize, SetSetup(small))
+
+	_, err = ParseSetup(bytes.NewReader([]byte(`{"g1_lagrange": ["0x00"], "g2_monomial": []}`)))
+	assert.NotEqual(t, nil, err)
+	_, err = ParseSetupBinary(bytes.NewReader([]byte("NOTASETUP")))
+	assert.Equal(t, ErrInvalidSetup, err)
+}
+
+func TestSetupCache(t *testing.T) {
+	config, err := content.ReadFile("trusted_setup.json")
+	assert.Equal(t, nil, err)
+	setup, err := ParseSetupJSON(bytes.NewReader(config))
+	assert.Equal(t, nil, err)
+
+	var buf bytes.Buffer
+	assert.Equal(t, nil, setup.WriteCache(&buf))
+	fromCache, err := ParseSetupCache(buf.Bytes())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, setup, fromCache)
+
+	// the cache file is memory-mapped, and recognised by LoadSetupFile
+	path := filepath.Join(t.TempDir(), "setup.cache")
+	assert.Equal(t, nil, setup.WriteCacheFile(path))
+	fromFile, err := LoadSetupCache(path)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, setup, fromFile)
+	fromFile, err = LoadSetupFile(path)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, setup, fromFile)
+	fromReader, err := ParseSetup(bytes.NewReader(buf.Bytes()))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, setup, fromReader)
+
+	// a flipped bit fails the checksum, a truncated cache its size
+	corrupt := bytes.Clone(buf.Bytes())
+	corrupt[setupCacheHeader+5] ^= 1
+	_, err = ParseSetupCache(corrupt)
+	assert.Equal(t, ErrSetupChecksum, err)
+	_, err = ParseSetupCache(buf.Bytes()[:buf.Len()-1])
+	assert.Equal(t, ErrInvalidSetup, err)
+}
+
+func TestValidateSetup(t *testing.T) {
+	config, err := content.ReadFile("trusted_setup.json")
+	assert.Equal(t, nil, err)
+	setup, err := ParseSetupJSONValidated(bytes.NewReader(config))
+	assert.Equal(t, nil, err)
+
+	path := filepath.Join(t.TempDir(), "setup.cache")
+	assert.Equal(t, nil, setup.WriteCacheFile(path))
+	fromFile, err := LoadSetupFileValidated(path)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, setup, fromFile)
+
+	// the lagrange points are the inverse FFT of the monomial points
+	assert.Equal(t, nil, setup.ValidateMonomial(DefaultKZGContext().monomialG1()))
+
+	// tampered setups are checked at a small width
+	dev, err := NewInsecureDevSetup(16, big.NewInt(1234))
+	assert.Equal(t, nil, err)
+	setup = &dev.Setup
+	shifted := append([]bls12381.G1Affine{}, dev.MonomialG1...)
+	shifted[1], shifted[2] = shifted[2], shifted[1]
+	assert.ErrorIs(t, setup.ValidateMonomial(shifted), ErrInvalidSetup)
+
+	// swapped points are in the subgroups but are not the powers of a secret
+	swapped := &Setup{G1: setup.G1, LagrangeG1: append([]bls12381.G1Affine{}, setup.LagrangeG1...), G2: setup.G2}
+	swapped.LagrangeG1[1], swapped.LagrangeG1[2] = swapped.LagrangeG1[2], swapped.LagrangeG1[1]
+	assert.ErrorIs(t, swapped.Validate(), ErrInvalidSetup)
+
+	swapped = &Setup{G1: setup.G1, LagrangeG1: setup.LagrangeG1, G2: append([]bls12381.G2Affine{}, setup.G2...)}
+	swapped.G2[2], swapped.G2[3] = swapped.G2[3], swapped.G2[2]
+	assert.ErrorIs(t, swapped.Validate(), ErrInvalidSetup)
+
+	// a point off the curve fails the subgroup checks
+	offCurve := &Setup{G1: setup.G1, LagrangeG1: append([]bls12381.G1Affine{}, setup.LagrangeG1...), G2: setup.G2}
+	offCurve.LagrangeG1[7].Y.SetOne()
+	err = offCurve.Validate()
+	assert.ErrorIs(t, err, ErrInvalidSetup)
+	assert.Contains(t, err.Error(), "lagrange point 7")
+}
+
+func TestInsecureDevSetup(t *testing.T) {
+	setup, err := NewInsecureDevSetup(16, big.NewInt(1234))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 16, len(setup.LagrangeG1))
+	assert.Equal(t, nil, setup.Validate())
+	assert.Equal(t, nil, setup.ValidateMonomial(setup.MonomialG1))
+
+	// the same secret gives the same setup, exported in the trusted_setup.json format
+	again, err := NewInsecureDevSetup(16, big.NewInt(1234))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, setup, again)
+
+	path := filepath.Join(t.TempDir(), "trusted_setup.json")
+	assert.Equal(t, nil, setup.WriteJSONFile(path))
+	fromFile, err := LoadSetupFileValidated(path)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, &setup.Setup, fromFile)
+
+	var buf bytes.Buffer
+	assert.Equal(t, nil, setup.Setup.WriteJSON(&buf))
+	fromJSON, err := ParseSetupJSONValidated(&buf)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, &setup.Setup, fromJSON)
+
+	_, err = NewInsecureDevSetup(12, big.NewInt(1234))
+	assert.ErrorIs(t, err, ErrInvalidSetup)
+	_, err = NewInsecureDevSetup(16, big.NewInt(0))
+	assert.ErrorIs(t, err, crateKzg.ErrZeroSecret)
+}
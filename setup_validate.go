@@ -0,0 +1,213 @@
+package fastcommit
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	crateKzg "github/yyjia/fastcommit/crateKzg/kzg"
+	"io"
+	"os"
+	"runtime"
+	"sync"
+)
+
+// ParseSetupJSONValidated reads a setup in the JSON format of trusted_setup.json and validates it.
+//
+// When the file holds the monomial G1 points, the lagrange points must also be their inverse FFT.
+func ParseSetupJSONValidated(r io.Reader) (*Setup, error) {
+	s, monomial, err := parseSetupJSON(r)
+	if nil != err {
+		return nil, err
+	}
+	if len(monomial) > 0 {
+		points, err := parseG1PointsNoSubgroupCheck(monomial)
+		if nil != err {
+			return nil, err
+		}
+		if err = s.ValidateMonomial(points); nil != err {
+			return nil, err
+		}
+	}
+	return s, s.Validate()
+}
+
+// LoadSetupFileValidated reads a setup from a file in any format, as LoadSetupFile does, and validates it.
+// Setups supplied at runtime should be loaded this way.
+func LoadSetupFileValidated(path string) (*Setup, error) {
+	f, err := os.Open(path)
+	if nil != err {
+		return nil, err
+	}
+	defer f.Close()
+
+	br := bufio.NewReader(f)
+	magic, err := br.Peek(len(setupBinaryMagic))
+	if nil == err && (bytes.Equal(magic, []byte(setupBinaryMagic)) || bytes.Equal(magic, []byte(setupCacheMagic))) {
+		s, err := LoadSetupFile(path)
+		if nil != err {
+			return nil, err
+		}
+		return s, s.Validate()
+	}
+	return ParseSetupJSONValidated(br)
+}
+
+// Validate checks that every point of the setup is in its prime-order subgroup, and that the points
+// are the powers of one secret α: the lagrange points in monomial form are [α^i]G₁, starting from the
+// generator, and the G2 points are [α^i]G₂.
+func (s *Setup) Validate() error {
+	if err := s.checkSizes(); nil != err {
+		return err
+	}
+	if err := s.checkSubgroups(); nil != err {
+		return err
+	}
+
+	monomial := crateKzg.NewDomain(uint64(len(s.LagrangeG1))).FftG1(s.LagrangeG1)
+	if !monomial[0].Equal(&s.G1) {
+		return fmt.Errorf("%w: the lagrange points do not sum to the G1 generator", ErrInvalidSetup)
+	}
+	return checkPowers(monomial, s.G2)
+}
+
+// checkPowers checks that monomial are the powers [α^i]G₁ of the secret α of the G2 powers [α^i]G₂,
+// with two pairing checks over random linear combinations.
+func checkPowers(monomial []bls12381.G1Affine, g2 []bls12381.G2Affine) error {
+	// e(Σ r_i [α^(i+1)]G₁, G₂) = e(Σ r_i [α^i]G₁, [α]G₂)
+	if len(monomial) > 1 {
+		ok, err := pairingPowersCheck(monomial[1:], monomial[:len(monomial)-1], g2[0], g2[1])
+		if nil != err {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("%w: the G1 points are not the powers of the secret of the G2 points", ErrInvalidSetup)
+		}
+	}
+
+	// e([α]G₁, Σ r_i [α^i]G₂) = e(G₁, Σ r_i [α^(i+1)]G₂)
+	if len(g2) > 2 && len(monomial) > 1 {
+		ok, err := pairingPowersCheckG2(g2[:len(g2)-1], g2[1:], monomial[1], monomial[0])
+		if nil != err {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("%w: the G2 points are not successive powers of the secret", ErrInvalidSetup)
+		}
+	}
+	return nil
+}
+
+// ValidateMonomial checks that the lagrange points of the setup are the inverse FFT of monomial,
+// the points [α^i]G₁ of the same ceremony.
+func (s *Setup) ValidateMonomial(monomial []bls12381.G1Affine) error {
+	if len(monomial) != len(s.LagrangeG1) {
+		return fmt.Errorf("%w: %d monomial points for %d lagrange points", ErrInvalidSetup, len(monomial), len(s.LagrangeG1))
+	}
+	if !monomial[0].Equal(&s.G1) {
+		return fmt.Errorf("%w: the first monomial point is not the G1 generator", ErrInvalidSetup)
+	}
+	lagrange := crateKzg.NewDomain(uint64(len(monomial))).IfftG1(monomial)
+	for i := range lagrange {
+		if !lagrange[i].Equal(&s.LagrangeG1[i]) {
+			return fmt.Errorf("%w: lagrange point %d does not match the monomial points", ErrInvalidSetup, i)
+		}
+	}
+	return nil
+}
+
+// checkSubgroups checks that every point is on its curve and in the prime-order subgroup,
+// and that the generators are not the point at infinity.
+func (s *Setup) checkSubgroups() error {
+	if s.G1.IsInfinity() || !s.G1.IsOnCurve() || !s.G1.IsInSubGroup() {
+		return fmt.Errorf("%w: the G1 generator is not in the G1 subgroup", ErrInvalidSetup)
+	}
+	if s.G2[0].IsInfinity() {
+		return fmt.Errorf("%w: the first G2 point is the point at infinity", ErrInvalidSetup)
+	}
+
+	return checkPointSubgroups("lagrange", s.LagrangeG1, s.G2)
+}
+
+// checkPointSubgroups checks that every point is on its curve and in the prime-order subgroup.
+// The G1 points are named kind in the errors.
+func checkPointSubgroups(kind string, g1 []bls12381.G1Affine, g2 []bls12381.G2Affine) error {
+	n := len(g1) + len(g2)
+	errs := make([]error, n)
+
+	// one chunk of points per CPU, the G2 points follow the G1 points
+	chunk := (n + runtime.NumCPU() - 1) / runtime.NumCPU()
+	var wg sync.WaitGroup
+	for start := 0; start < n; start += chunk {
+		end := start + chunk
+		if end > n {
+			end = n
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			for j := start; j < end; j++ {
+				if j < len(g1) {
+					if !g1[j].IsOnCurve() || !g1[j].IsInSubGroup() {
+						errs[j] = fmt.Errorf("%w: %s point %d is not in the G1 subgroup", ErrInvalidSetup, kind, j)
+					}
+					continue
+				}
+				if k := j - len(g1); !g2[k].IsOnCurve() || !g2[k].IsInSubGroup() {
+					errs[j] = fmt.Errorf("%w: G2 point %d is not in the G2 subgroup", ErrInvalidSetup, k)
+				}
+			}
+		}(start, end)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// pairingPowersCheck checks e(Σ r_i a_i, q0) = e(Σ r_i b_i, q1) for random r_i.
+func pairingPowersCheck(a, b []bls12381.G1Affine, q0, q1 bls12381.G2Affine) (bool, error) {
+	r, err := randomScalars(len(a))
+	if nil != err {
+		return false, err
+	}
+	var sumA, sumB bls12381.G1Affine
+	if _, err = sumA.MultiExp(a, r, ecc.MultiExpConfig{}); nil != err {
+		return false, err
+	}
+	if _, err = sumB.MultiExp(b, r, ecc.MultiExpConfig{}); nil != err {
+		return false, err
+	}
+	sumB.Neg(&sumB)
+	return bls12381.PairingCheck([]bls12381.G1Affine{sumA, sumB}, []bls12381.G2Affine{q0, q1})
+}
+
+// pairingPowersCheckG2 checks e(p0, Σ r_i a_i) = e(p1, Σ r_i b_i) for random r_i.
+func pairingPowersCheckG2(a, b []bls12381.G2Affine, p0, p1 bls12381.G1Affine) (bool, error) {
+	r, err := randomScalars(len(a))
+	if nil != err {
+		return false, err
+	}
+	var sumA, sumB bls12381.G2Affine
+	if _, err = sumA.MultiExp(a, r, ecc.MultiExpConfig{}); nil != err {
+		return false, err
+	}
+	if _, err = sumB.MultiExp(b, r, ecc.MultiExpConfig{}); nil != err {
+		return false, err
+	}
+	p1.Neg(&p1)
+	return bls12381.PairingCheck([]bls12381.G1Affine{p0, p1}, []bls12381.G2Affine{sumA, sumB})
+}
+
+// randomScalars returns n scalars drawn from crypto/rand.
+func randomScalars(n int) ([]fr.Element, error) {
+	r := make([]fr.Element, n)
+	for i := range r {
+		if _, err := r[i].SetRandom(); nil != err {
+			return nil, err
+		}
+	}
+	return r, nil
+}
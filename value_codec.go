@@ -0,0 +1,221 @@
+package fastcommit
+
+import (
+	"errors"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+const (
+	// HalfSize is the number of bytes stored in each slot by CodecHalves.
+	HalfSize = 16
+	// ChunkSize is the number of bytes stored in each slot by CodecChunks.
+	ChunkSize = 31
+	// MaxChunksSize is the length of the largest value stored by CodecChunks, a branch worth of chunks.
+	MaxChunksSize = POLY_SIZE * ChunkSize
+)
+
+var (
+	ErrInvalidValueSize = errors.New("value size does not fit the codec")
+	ErrInvalidEncoding  = errors.New("field elements are not a valid encoding")
+	ErrUnknownCodec     = errors.New("unknown value codec")
+)
+
+// ValueCodec maps byte strings onto consecutive slots, so that values larger than a
+// field element are committed without hashing away their preimage.
+type ValueCodec int
+
+const (
+	// CodecHalves stores a 32-byte value as its two 16-byte halves in two slots.
+	CodecHalves ValueCodec = iota
+	// CodecChunks stores a byte string of any length as a length prefix followed
+	// by 31-byte chunks, one per slot. The last chunk is padded with zeroes.
+	CodecChunks
+)
+
+// NumSlots returns the number of slots used by a value of length size, 0 for an unknown codec.
+func (c ValueCodec) NumSlots(size int) int {
+	switch c {
+	case CodecHalves:
+		return 2
+	case CodecChunks:
+		return 1 + (size+ChunkSize-1)/ChunkSize
+	}
+	return 0
+}
+
+// Encode returns the field elements holding data.
+func (c ValueCodec) Encode(data []byte) ([]fr.Element, error) {
+	switch c {
+	case CodecHalves:
+		if len(data) != 2*HalfSize {
+			return nil, ErrInvalidValueSize
+		}
+		return []fr.Element{
+			*new(fr.Element).SetBytes(data[:HalfSize]),
+			*new(fr.Element).SetBytes(data[HalfSize:]),
+		}, nil
+	case CodecChunks:
+		if len(data) > MaxChunksSize {
+			return nil, ErrInvalidValueSize
+		}
+	default:
+		return nil, ErrUnknownCodec
+	}
+
+	elems := make([]fr.Element, c.NumSlots(len(data)))
+	elems[0].SetUint64(uint64(len(data)))
+	for i := 1; i < len(elems); i++ {
+		chunk := data[(i-1)*ChunkSize:]
+		if len(chunk) > ChunkSize {
+			chunk = chunk[:ChunkSize]
+		}
+		// left-align the last chunk so the padding sits in the low bytes
+		var buf [ChunkSize]byte
+		copy(buf[:], chunk)
+		elems[i].SetBytes(buf[:])
+	}
+	return elems, nil
+}
+
+// Decode returns the byte string held by elems.
+//
+// It rejects any elements that Encode can not produce, so every value has exactly one encoding.
+func (c ValueCodec) Decode(elems []fr.Element) ([]byte, error) {
+	switch c {
+	case CodecHalves:
+		if len(elems) != 2 {
+			return nil, ErrInvalidEncoding
+		}
+		res := make([]byte, 0, 2*HalfSize)
+		for i := range elems {
+			b := elems[i].Bytes()
+			if !isZeroes(b[:len(b)-HalfSize]) {
+				return nil, ErrInvalidEncoding
+			}
+			res = append(res, b[len(b)-HalfSize:]...)
+		}
+		return res, nil
+	case CodecChunks:
+	default:
+		return nil, ErrUnknownCodec
+	}
+
+	if len(elems) == 0 || !elems[0].IsUint64() {
+		return nil, ErrInvalidEncoding
+	}
+	size := elems[0].Uint64()
+	if size > uint64(len(elems))*ChunkSize || len(elems) != c.NumSlots(int(size)) {
+		return nil, ErrInvalidEncoding
+	}
+	res := make([]byte, 0, len(elems)*ChunkSize)
+	for i := 1; i < len(elems); i++ {
+		b := elems[i].Bytes()
+		if b[0] != 0 {
+			return nil, ErrInvalidEncoding
+		}
+		res = append(res, b[1:]...)
+	}
+	if !isZeroes(res[size:]) {
+		return nil, ErrInvalidEncoding
+	}
+	return res[:size], nil
+}
+
+func isZeroes(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// UpdateBytes stores data in consecutive slots starting at index, in the branches of DefaultKZGContext.
+func UpdateBytes(index int, codec ValueCodec, data []byte) error {
+	return DefaultKZGContext().UpdateBytes(index, codec, data)
+}
+
+// UpdateBytes stores data in consecutive slots starting at index.
+//
+// It rejects the values that ProofForBytes could not open: those of an unknown codec,
+// and those longer than MaxChunksSize for CodecChunks.
+func (c *KZGContext) UpdateBytes(index int, codec ValueCodec, data []byte) error {
+	elems, err := codec.Encode(data)
+	if nil != err {
+		return err
+	}
+	for i := range elems {
+		if err := c.Updates(index+i, elems[i]); nil != err {
+			return err
+		}
+	}
+	return nil
+}
+
+// BytesProof opens a value stored by UpdateBytes, one opening per slot.
+type BytesProof struct {
+	Data   []byte
+	Proofs []bls12381.G1Affine
+}
+
+// ProofForBytes is ProofForBytes of DefaultKZGContext.
+func ProofForBytes(index int, codec ValueCodec) (*BytesProof, error) {
+	return DefaultKZGContext().ProofForBytes(index, codec)
+}
+
+// ProofForBytes opens the value stored at index, returning the decoded bytes.
+func (c *KZGContext) ProofForBytes(index int, codec ValueCodec) (*BytesProof, error) {
+	var numSlots int
+	switch codec {
+	case CodecHalves:
+		numSlots = 2
+	case CodecChunks:
+		// the length prefix tells how many slots follow it
+		blob := index / POLY_SIZE
+		if index < 0 || blob >= len(c.branchs) {
+			return nil, ErrSlotOutOfRange
+		}
+		prefix := c.branchs[blob].values[index%POLY_SIZE]
+		if !prefix.IsUint64() || prefix.Uint64() > MaxChunksSize {
+			return nil, ErrInvalidEncoding
+		}
+		numSlots = codec.NumSlots(int(prefix.Uint64()))
+	default:
+		return nil, ErrUnknownCodec
+	}
+
+	vals, proofs, err := c.proofForSlots(consecutiveSlots(index, numSlots))
+	if nil != err {
+		return nil, err
+	}
+	data, err := codec.Decode(vals)
+	if nil != err {
+		return nil, err
+	}
+	return &BytesProof{Data: data, Proofs: proofs}, nil
+}
+
+// Verify is VerifyBytesProof of DefaultKZGContext.
+func (p *BytesProof) Verify(index int, codec ValueCodec, commits map[int]bls12381.G1Affine) error {
+	return DefaultKZGContext().VerifyBytesProof(p, index, codec, commits)
+}
+
+// VerifyBytesProof checks the value of p against the commitments of the branches, keyed by branch number.
+//
+// The slots are recomputed from the bytes, so a valid proof binds exactly p.Data.
+func (c *KZGContext) VerifyBytesProof(p *BytesProof, index int, codec ValueCodec, commits map[int]bls12381.G1Affine) error {
+	elems, err := codec.Encode(p.Data)
+	if nil != err {
+		return err
+	}
+	return c.verifySlots(consecutiveSlots(index, len(elems)), elems, p.Proofs, commits)
+}
+
+func consecutiveSlots(index, n int) []int {
+	slots := make([]int, n)
+	for i := range slots {
+		slots[i] = index + i
+	}
+	return slots
+}
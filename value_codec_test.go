@@ -0,0 +1,87 @@
+package fastcommit
+
+import (
+	"crypto/rand"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestValueCodec_EncodeDecode(t *testing.T) {
+	for _, size := range []int{0, 1, 30, 31, 32, 100} {
+		data := make([]byte, size)
+		rand.Read(data)
+
+		elems, err := CodecChunks.Encode(data)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, CodecChunks.NumSlots(size), len(elems))
+
+		got, err := CodecChunks.Decode(elems)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, data, got)
+	}
+
+	data := make([]byte, 32)
+	rand.Read(data)
+	elems, err := CodecHalves.Encode(data)
+	assert.Equal(t, nil, err)
+	got, err := CodecHalves.Decode(elems)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, data, got)
+
+	_, err = CodecHalves.Encode(data[:31])
+	assert.Equal(t, ErrInvalidValueSize, err)
+
+	_, err = CodecChunks.Encode(make([]byte, MaxChunksSize+1))
+	assert.Equal(t, ErrInvalidValueSize, err)
+	_, err = ValueCodec(2).Encode(data)
+	assert.Equal(t, ErrUnknownCodec, err)
+	_, err = ValueCodec(2).Decode(elems)
+	assert.Equal(t, ErrUnknownCodec, err)
+}
+
+func TestValueCodec_DecodeNonCanonical(t *testing.T) {
+	elems, _ := CodecChunks.Encode([]byte{1, 2, 3})
+	// non-zero padding
+	elems[1].Add(&elems[1], new(fr.Element).SetOne())
+	_, err := CodecChunks.Decode(elems)
+	assert.Equal(t, ErrInvalidEncoding, err)
+
+	// length prefix does not match the number of chunks
+	elems, _ = CodecChunks.Encode([]byte{1, 2, 3})
+	elems[0].SetUint64(40)
+	_, err = CodecChunks.Decode(elems)
+	assert.Equal(t, ErrInvalidEncoding, err)
+
+	// half larger than 16 bytes
+	elems = []fr.Element{fr.NewElement(1), *new(fr.Element).SetBytes(append([]byte{1}, make([]byte, 16)...))}
+	_, err = CodecHalves.Decode(elems)
+	assert.Equal(t, ErrInvalidEncoding, err)
+}
+
+func TestBytesProof_Verify(t *testing.T) {
+	data := make([]byte, 80)
+	rand.Read(data)
+	index := 3*POLY_SIZE + 10
+	err := UpdateBytes(index, CodecChunks, data)
+	assert.Equal(t, nil, err)
+
+	proof, err := ProofForBytes(index, CodecChunks)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, data, proof.Data)
+
+	commits := map[int]bls12381.G1Affine{3: DefaultKZGContext().branchs[3].commit}
+	err = proof.Verify(index, CodecChunks, commits)
+	assert.Equal(t, nil, err)
+
+	proof.Data[7]++
+	err = proof.Verify(index, CodecChunks, commits)
+	assert.NotEqual(t, nil, err)
+
+	// what can not be proven is not written
+	assert.Equal(t, ErrInvalidValueSize, UpdateBytes(index, CodecChunks, make([]byte, MaxChunksSize+1)))
+	assert.Equal(t, ErrUnknownCodec, UpdateBytes(index, ValueCodec(2), data))
+	_, err = ProofForBytes(index, ValueCodec(2))
+	assert.Equal(t, ErrUnknownCodec, err)
+}
@@ -14,8 +14,10 @@ import (
 const POLY_SIZE = 4096
 
 var (
-	ErrFullSize = errors.New("array size is full")
-	ErrMissKey  = errors.New("miss key")
+	ErrFullSize        = errors.New("array size is full")
+	ErrMissKey         = errors.New("miss key")
+	ErrSlotOutOfRange  = errors.New("slot out of range")
+	ErrInvalidNumProof = errors.New("number of proofs is not the same as the number of slots")
 )
 
 type Account struct {
@@ -28,38 +30,118 @@ type ValueCommit struct {
 	//keys   map[fr.Element]int
 	values []fr.Element
 	//size   int
+	// proofs caches the opening proofs of every slot, nil until AllProofs is called
+	proofs []bls12381.G1Affine
+	// stale holds the changes of the slots updated since proofs was computed, keyed by slot;
+	// they are applied to the cached proofs when these are read
+	stale map[int]fr.Element
+	// ctx is the setup the branch is committed with, DefaultKZGContext when nil
+	ctx *KZGContext
 }
 
-var branchs []ValueCommit
+// context returns the setup the branch is committed with.
+func (s *ValueCommit) context() *KZGContext {
+	return s.ctx.orDefault()
+}
+
+// C returns the commitment of the branch.
+func (s *ValueCommit) C() *bls12381.G1Affine {
+	return &s.commit
+}
 
+// Updates sets slot indexs of the branches of DefaultKZGContext to stat.
 func Updates(indexs int, stat fr.Element) error {
+	return DefaultKZGContext().Updates(indexs, stat)
+}
+
+// Updates sets slot indexs of the branches of c to stat, committing to empty branches up to it.
+func (c *KZGContext) Updates(indexs int, stat fr.Element) error {
 	blob := indexs / POLY_SIZE
 	idx := indexs % POLY_SIZE
 
-	if blob >= len(branchs) {
+	for blob >= len(c.branchs) {
 		vals := make([]fr.Element, POLY_SIZE)
-		c, err := kzg.Commit(vals, srs.Pk, 0)
+		commit, err := kzg.Commit(vals, c.srs.Pk, 0)
 		if nil != err {
 			return err
 		}
 
-		branchs = append(branchs, ValueCommit{
+		c.branchs = append(c.branchs, &ValueCommit{
 			values: vals,
-			commit: c,
+			commit: commit,
+			ctx:    c,
 		})
 	}
-	branchs[blob].Update(idx, stat)
+	return c.branchs[blob].Update(idx, stat)
+}
+
+// UpdatesRoot commits the levels above the branches of DefaultKZGContext.
+func UpdatesRoot() {
+	DefaultKZGContext().UpdatesRoot()
+}
+
+// UpdatesRoot commits the levels above the leaf branches of c, filled by Updates, up to the root.
+// Multiproofs and the root hash read these levels, so it must be called once the leaves are updated.
+func (c *KZGContext) UpdatesRoot() {
+	c.branchs1 = c.ParentBranches(0, c.branchs)
+	c.branchs2 = c.ParentBranches(1, c.branchs1)
+}
+
+// proofForSlots opens every slot on its own against the branch of c holding it.
+func (c *KZGContext) proofForSlots(slots []int) ([]fr.Element, []bls12381.G1Affine, error) {
+	vals := make([]fr.Element, len(slots))
+	proofs := make([]bls12381.G1Affine, len(slots))
+	for i, slot := range slots {
+		blob := slot / POLY_SIZE
+		idx := slot % POLY_SIZE
+		if slot < 0 || blob >= len(c.branchs) {
+			return nil, nil, ErrSlotOutOfRange
+		}
+
+		vals[i] = c.branchs[blob].values[idx]
+		proof, err := c.branchs[blob].ProofForIndex(idx)
+		if nil != err {
+			return nil, nil, err
+		}
+		proofs[i] = proof
+	}
+	return vals, proofs, nil
+}
+
+// verifySlots checks openings made by proofForSlots against the commitments of the branches, keyed by branch number,
+// over the setup of c.
+func (c *KZGContext) verifySlots(slots []int, vals []fr.Element, proofs []bls12381.G1Affine, commits map[int]bls12381.G1Affine) error {
+	if len(vals) != len(slots) || len(proofs) != len(slots) {
+		return ErrInvalidNumProof
+	}
+	for i, slot := range slots {
+		commit, ok := commits[slot/POLY_SIZE]
+		if slot < 0 || !ok {
+			return ErrSlotOutOfRange
+		}
+
+		vc := ValueCommit{commit: commit, ctx: c}
+		if err := vc.VerifyForVal(c.domain.Roots[slot%POLY_SIZE], vals[i], proofs[i]); nil != err {
+			return err
+		}
+	}
 	return nil
 }
 
+// NewContext commits to data with DefaultKZGContext.
 func NewContext(data []Account) *ValueCommit {
+	return DefaultKZGContext().NewValueCommit(data)
+}
+
+// NewValueCommit commits to data with the setup of c.
+func (c *KZGContext) NewValueCommit(data []Account) *ValueCommit {
 	//keyInd := make(map[fr.Element]int, POLY_SIZE)
 	vals := make([]fr.Element, POLY_SIZE)
 	for i := 0; i < len(data); i++ {
 		//keyInd[data[i].key] = i
 		vals[i] = data[i].state
 	}
-	c, err := kzg.Commit(vals, srs.Pk, 0)
+	commit, err := kzg.Commit(vals, c.srs.Pk, 0)
 	if nil != err {
 		panic(err)
 	}
@@ -67,7 +149,8 @@ func NewContext(data []Account) *ValueCommit {
 		//keys:   keyInd,
 		values: vals,
 		//size:   len(data),
-		commit: c,
+		commit: commit,
+		ctx:    c,
 	}
 }
 
@@ -81,11 +164,25 @@ func (s *ValueCommit) Update(index int, v fr.Element) error {
 	//bInt := new(big.Int)
 	//sub.BigInt(bInt)
 
-	bInt := new(fr.Element).Sub(&v, &s.values[index]).BigInt(new(big.Int))
+	delta := new(fr.Element).Sub(&v, &s.values[index])
+	bInt := delta.BigInt(new(big.Int))
 
-	addC := new(bls12381.G1Affine).ScalarMultiplication(&srs.Pk.G1[index], bInt)
+	addC := new(bls12381.G1Affine).ScalarMultiplication(&s.context().srs.Pk.G1[index], bInt)
 	s.commit = *new(bls12381.G1Affine).Add(&s.commit, addC)
 	s.values[index] = v
+
+	// the cached proofs are adjusted when read, until recomputing them is cheaper
+	if s.proofs != nil {
+		d, ok := s.stale[index]
+		if !ok && len(s.stale) >= maxStaleSlots {
+			s.proofs, s.stale = nil, nil
+			return nil
+		}
+		if s.stale == nil {
+			s.stale = make(map[int]fr.Element)
+		}
+		s.stale[index] = *d.Add(&d, delta)
+	}
 	return nil
 }
 
@@ -120,7 +217,8 @@ func (s *ValueCommit) BatchUpdate(indexs []int, vals []fr.Element) error {
 func (s *ValueCommit) Proof() (bls12381.G1Affine, error) {
 	evaluationChallenge := computeChallenge(s.values, s.commit)
 
-	openingProof, err := crateKzg.Open(domains, s.values, evaluationChallenge, &crateKzg.CommitKey{G1: srs.Pk.G1}, 0)
+	ctx := s.context()
+	openingProof, err := crateKzg.Open(ctx.domain, s.values, evaluationChallenge, ctx.commitKey(), 0)
 	if err != nil {
 		return bls12381.G1Affine{}, err
 	}
@@ -129,7 +227,8 @@ func (s *ValueCommit) Proof() (bls12381.G1Affine, error) {
 }
 
 func (s *ValueCommit) ProofForVal(evaluation fr.Element) (bls12381.G1Affine, error) {
-	openingProof, err := crateKzg.Open(domains, s.values, evaluation, &crateKzg.CommitKey{G1: srs.Pk.G1}, 0)
+	ctx := s.context()
+	openingProof, err := crateKzg.Open(ctx.domain, s.values, evaluation, ctx.commitKey(), 0)
 	if nil != err {
 		return bls12381.G1Affine{}, err
 	}
@@ -137,28 +236,94 @@ func (s *ValueCommit) ProofForVal(evaluation fr.Element) (bls12381.G1Affine, err
 	return openingProof.QuotientCommitment, nil
 }
 
+// ProofForVals opens the branch at several slots at once, keys must be points of the domain.
+//
+// The proof aggregates the single-slot proofs, so it is served from the AllProofs cache when there is one.
 func (s *ValueCommit) ProofForVals(keys []fr.Element) (bls12381.G1Affine, error) {
+	indexs, err := s.context().keyIndexs(keys)
+	if nil != err {
+		return bls12381.G1Affine{}, err
+	}
+	proofs := make([]bls12381.G1Affine, len(indexs))
+	for i, index := range indexs {
+		if proofs[i], err = s.ProofForIndex(index); nil != err {
+			return bls12381.G1Affine{}, err
+		}
+	}
+	return s.context().AggregateProofs(indexs, proofs)
+}
+
+// AggregateProofs combines single-slot proofs of one branch, possibly computed at different times,
+// into one proof for all the slots. The values of the slots are not needed.
+func AggregateProofs(indexs []int, proofs []bls12381.G1Affine) (bls12381.G1Affine, error) {
+	return DefaultKZGContext().AggregateProofs(indexs, proofs)
+}
 
-	return bls12381.G1Affine{}, nil
+// AggregateProofs is AggregateProofs over the domain of c.
+func (c *KZGContext) AggregateProofs(indexs []int, proofs []bls12381.G1Affine) (bls12381.G1Affine, error) {
+	idx := make([]uint64, len(indexs))
+	for i := range indexs {
+		if indexs[i] < 0 {
+			return bls12381.G1Affine{}, ErrSlotOutOfRange
+		}
+		idx[i] = uint64(indexs[i])
+	}
+	return c.domain.AggregateProofs(idx, proofs)
+}
+
+// keyIndexs returns the slots of keys, which must be points of the domain.
+func (c *KZGContext) keyIndexs(keys []fr.Element) ([]int, error) {
+	indexs := make([]int, len(keys))
+	for i := range keys {
+		indexs[i] = -1
+		for j := range c.domain.Roots {
+			if keys[i].Equal(&c.domain.Roots[j]) {
+				indexs[i] = j
+				break
+			}
+		}
+		if indexs[i] == -1 {
+			return nil, ErrMissKey
+		}
+	}
+	return indexs, nil
 }
 
 func (s *ValueCommit) Verify(proof bls12381.G1Affine) error {
 	evaluationChallenge := computeChallenge(s.values, s.commit)
-	outputPoint, err := domains.EvaluateLagrangePolynomial(s.values, evaluationChallenge)
+	outputPoint, err := s.context().domain.EvaluateLagrangePolynomial(s.values, evaluationChallenge)
 	if nil != err {
 		return err
 	}
-	return crateKzg.Verify(&s.commit, &crateKzg.OpeningProof{proof, evaluationChallenge, *outputPoint}, &crateKzg.OpeningKey{
-		srs.Vk.G1,
-		srs.Vk.G2[0],
-		srs.Vk.G2[1],
+	return s.context().getPreparedKey().Verify(&s.commit, &crateKzg.OpeningProof{
+		QuotientCommitment: proof,
+		InputPoint:         evaluationChallenge,
+		ClaimedValue:       *outputPoint,
 	})
 }
 
 func (s *ValueCommit) VerifyForVal(evaluation, output fr.Element, proof bls12381.G1Affine) error {
-	return crateKzg.Verify(&s.commit, &crateKzg.OpeningProof{proof, evaluation, output}, &crateKzg.OpeningKey{
-		srs.Vk.G1,
-		srs.Vk.G2[0],
-		srs.Vk.G2[1],
+	return s.context().getPreparedKey().Verify(&s.commit, &crateKzg.OpeningProof{
+		QuotientCommitment: proof,
+		InputPoint:         evaluation,
+		ClaimedValue:       output,
 	})
 }
+
+// VerifyForVals verifies a proof made by ProofForVals or AggregateProofs that the branch holds outputs at keys.
+func (s *ValueCommit) VerifyForVals(keys, outputs []fr.Element, proof bls12381.G1Affine) error {
+	ctx := s.context()
+	indexs, err := ctx.keyIndexs(keys)
+	if nil != err {
+		return err
+	}
+	idx := make([]uint64, len(indexs))
+	for i := range indexs {
+		idx[i] = uint64(indexs[i])
+	}
+	return crateKzg.VerifyAggregated(&s.commit, &crateKzg.AggregatedProof{
+		QuotientCommitment: proof,
+		Indices:            idx,
+		ClaimedValues:      outputs,
+	}, ctx.domain, ctx.commitKey(), ctx.setupG2)
+}
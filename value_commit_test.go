@@ -2,6 +2,7 @@ package fastcommit
 
 import (
 	"crypto/rand"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -95,17 +96,17 @@ func TestValueCommit_ProofForKey_VerifyForKey(t *testing.T) {
 	proof, err := fc.ProofForVal(p)
 	assert.Equal(t, nil, err)
 
-	output, err := domains.EvaluateLagrangePolynomial(fc.values, p)
+	output, err := DefaultKZGContext().domain.EvaluateLagrangePolynomial(fc.values, p)
 	assert.Equal(t, nil, err)
 
 	err = fc.VerifyForVal(p, *output, proof)
 	assert.Equal(t, nil, err)
 
-	k2 := domains.Roots[4000]
+	k2 := DefaultKZGContext().domain.Roots[4000]
 	proof, err = fc.ProofForVal(k2)
 	assert.Equal(t, nil, err)
 
-	output, err = domains.EvaluateLagrangePolynomial(fc.values, k2)
+	output, err = DefaultKZGContext().domain.EvaluateLagrangePolynomial(fc.values, k2)
 	assert.Equal(t, nil, err)
 	assert.Equal(t, fc.values[4000], *output)
 
@@ -113,8 +114,59 @@ func TestValueCommit_ProofForKey_VerifyForKey(t *testing.T) {
 	assert.Equal(t, nil, err)
 }
 
+func TestValueCommit_ProofForVals_VerifyForVals(t *testing.T) {
+	fc := NewContext(dataCase)
+
+	keys := []fr.Element{DefaultKZGContext().domain.Roots[3], DefaultKZGContext().domain.Roots[100], DefaultKZGContext().domain.Roots[4000]}
+	outputs := []fr.Element{fc.values[3], fc.values[100], fc.values[4000]}
+	proof, err := fc.ProofForVals(keys)
+	assert.Equal(t, nil, err)
+
+	err = fc.VerifyForVals(keys, outputs, proof)
+	assert.Equal(t, nil, err)
+
+	// proofs computed one by one aggregate to the same proof
+	proofs := make([]bls12381.G1Affine, len(keys))
+	for i := range keys {
+		proofs[i], err = fc.ProofForVal(keys[i])
+		assert.Equal(t, nil, err)
+	}
+	aggregated, err := AggregateProofs([]int{3, 100, 4000}, proofs)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, proof, aggregated)
+
+	outputs[1] = fr.NewElement(1)
+	err = fc.VerifyForVals(keys, outputs, proof)
+	assert.NotEqual(t, nil, err)
+
+	_, err = fc.ProofForVals([]fr.Element{fr.NewElement(9)})
+	assert.Equal(t, ErrMissKey, err)
+}
+
 func TestBranchs(t *testing.T) {
 	for i := uint64(0); i < 100000; i++ {
 		Updates(int(i), *new(fr.Element).SetUint64(i))
 	}
 }
+
+func TestUpdatesRoot(t *testing.T) {
+	// a context proves against its own tree, not the one of DefaultKZGContext
+	ctx := embeddedContext()
+	index := POLY_SIZE + 5
+	v := fr.NewElement(7)
+	assert.Equal(t, nil, ctx.Updates(index, v))
+	ctx.UpdatesRoot()
+	assert.Equal(t, 2, len(ctx.branchs))
+	assert.Equal(t, ctx.CommitmentToField(0, ctx.branchs[1].C()), ctx.branchs1[0].values[1])
+	assert.Equal(t, ctx.CommitmentToField(1, ctx.branchs1[0].C()), ctx.branchs2[0].values[0])
+
+	m := ctx.NewMaterial(uint32(index), v)
+	np := m.parseParams()
+	D, proof, err := m.Prove(np)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nil, m.Verify(np, D, proof))
+	// the key is bound into the transcript of the multiproof
+	assert.NotEqual(t, nil, ctx.NewMaterial(uint32(index)+1, v).Verify(np, D, proof))
+	assert.Equal(t, nil, m.VerifyRoot(ctx.RootHash(), np, D, proof))
+	assert.NotEqual(t, RootHash(), ctx.RootHash())
+}